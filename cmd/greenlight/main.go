@@ -2,20 +2,28 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"expvar"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"syscall"
 	"time"
 
+	"github.com/denpeshkov/greenlight/internal/filesystem"
 	"github.com/denpeshkov/greenlight/internal/greenlight"
 	"github.com/denpeshkov/greenlight/internal/http"
+	"github.com/denpeshkov/greenlight/internal/movieio"
 	"github.com/denpeshkov/greenlight/internal/multierr"
 	"github.com/denpeshkov/greenlight/internal/postgres"
+	"github.com/denpeshkov/greenlight/internal/webhook"
 
 	_ "github.com/lib/pq"
 )
@@ -30,6 +38,9 @@ type Config struct {
 		writeTimeout    time.Duration
 		shutdownTimeout time.Duration
 		maxRequestBody  int64
+		maxPosterSize   int64
+		maxImportSize   int64
+		duplicateMode   string
 	}
 
 	// HTTP request limiter
@@ -52,17 +63,54 @@ type Config struct {
 	token struct {
 		secret string
 	}
+
+	// Blob storage
+	blob struct {
+		dir     string
+		baseURL string
+		secret  string
+	}
+
+	// Movie import
+	imp struct {
+		format string
+		status string
+	}
+
+	// Catalogue statistics
+	stats struct {
+		refreshInterval time.Duration
+	}
+
+	// Movie change feed
+	changes struct {
+		sequenceInterval time.Duration
+	}
+
+	// Positional arguments
+	args []string
 }
 
 func main() {
 	logger := newLogger()
 
+	// Subcommands import movies instead of running the server.
+	args, runFn := os.Args[1:], run
+	if len(args) > 0 {
+		switch args[0] {
+		case "import":
+			args, runFn = args[1:], runImport
+		case "import-imdb":
+			args, runFn = args[1:], runImportIMDb
+		}
+	}
+
 	cfg := Config{}
-	if err := cfg.parseFlags(os.Args[1:]); err != nil {
+	if err := cfg.parseFlags(args); err != nil {
 		logger.Error("flags parsing error", "error", err)
 	}
 
-	if err := run(&cfg, logger); err != nil {
+	if err := runFn(&cfg, logger); err != nil {
 		logger.Error("application error", "error", err)
 		os.Exit(1)
 	}
@@ -78,16 +126,31 @@ func run(cfg *Config, logger *slog.Logger) error {
 		postgres.WithConnectionTimeout(cfg.pgDB.connTimeout),
 		postgres.WithQueryTimeout(cfg.pgDB.queryTimeout),
 	)
+	movieService := postgres.NewMovieService(db)
+	movieEventService := postgres.NewMovieEventService(db)
+	webhookService := postgres.NewWebhookService(db)
 	srv := http.NewServer(
 		cfg.http.addr,
-		postgres.NewMovieService(db),
+		movieService,
 		postgres.NewUserService(db),
 		greenlight.NewAuthService(cfg.token.secret),
+		http.WithBlobStore(filesystem.NewBlobStore(cfg.blob.dir, cfg.blob.baseURL, cfg.blob.secret)),
+		http.WithPersonService(postgres.NewPersonService(db)),
+		http.WithReviewService(postgres.NewReviewService(db)),
+		http.WithListService(postgres.NewListService(db)),
+		http.WithCollectionService(postgres.NewCollectionService(db)),
+		http.WithGenreService(postgres.NewGenreService(db)),
+		http.WithTagService(postgres.NewTagService(db)),
+		http.WithMovieEventService(movieEventService),
+		http.WithWebhookService(webhookService),
 		http.WithIdleTimeout(cfg.http.idleTimeout),
 		http.WithReadTimeout(cfg.http.readTimeout),
 		http.WithWriteTimeout(cfg.http.writeTimeout),
 		http.WithShutdownTimeout(cfg.http.shutdownTimeout),
 		http.WithMaxRequestBody(cfg.http.maxRequestBody),
+		http.WithMaxPosterSize(cfg.http.maxPosterSize),
+		http.WithMaxImportSize(cfg.http.maxImportSize),
+		http.WithDuplicateMode(greenlight.DuplicateMode(cfg.http.duplicateMode)),
 		http.WithLimiterRps(cfg.limiter.rps),
 		http.WithLimiterBurst(cfg.limiter.burst),
 	)
@@ -102,6 +165,8 @@ func run(cfg *Config, logger *slog.Logger) error {
 	}))
 
 	// Application graceful shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
 	shutdownErr := make(chan error)
 	go func() {
 		quit := make(chan os.Signal, 1)
@@ -110,7 +175,11 @@ func run(cfg *Config, logger *slog.Logger) error {
 
 		<-quit
 		logger.Debug("shutting down HTTP server")
-		shutdownErr <- srv.Close()
+		srvErr := srv.Close()
+		stopJobs()
+
+		logger.Debug("shutting down movie events")
+		shutdownErr <- multierr.Join(srvErr, movieEventService.Close())
 
 		logger.Debug("shutting down database")
 		shutdownErr <- db.Close()
@@ -123,6 +192,14 @@ func run(cfg *Config, logger *slog.Logger) error {
 	}
 	logger.Debug("database connection established")
 
+	go refreshStats(jobsCtx, movieService, cfg.stats.refreshInterval, logger)
+	go sequenceChanges(jobsCtx, movieService, cfg.changes.sequenceInterval, logger)
+	go webhook.NewDispatcher(webhookService).Run(jobsCtx)
+
+	if err := movieEventService.Open(); err != nil {
+		return fmt.Errorf("listening for movie events: %w", err)
+	}
+
 	// Setting up HTTP server
 	err = srv.Open()
 	if err != nil {
@@ -136,6 +213,156 @@ func run(cfg *Config, logger *slog.Logger) error {
 	return multierr.Join(srvErr, dbErr)
 }
 
+// refreshStats refreshes the catalogue statistics every interval until the context is canceled.
+func refreshStats(ctx context.Context, movieService greenlight.MovieService, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := movieService.RefreshStats(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("refreshing statistics", "error", err)
+			}
+		}
+	}
+}
+
+// runImport imports movies from the file given as the positional argument, or from the standard input if it is "-".
+// The movies are created with the "-import-status" status, published by default, since they have no user to own them.
+// The report of the import is written to the standard output.
+func runImport(cfg *Config, logger *slog.Logger) (err error) {
+	if len(cfg.args) != 1 {
+		return errors.New("usage: greenlight import [flags] <file>")
+	}
+	name := cfg.args[0]
+
+	format := cfg.imp.format
+	if format == "" {
+		format = filepath.Ext(name)
+	}
+	f, err := movieio.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+
+	// The imported movies have no user to own them, so drafts would be visible only to the editors.
+	status := greenlight.MovieStatus(cfg.imp.status)
+	if status != greenlight.MovieDraft && status != greenlight.MoviePublished {
+		return fmt.Errorf("invalid import status %q: must be %q or %q", status, greenlight.MovieDraft, greenlight.MoviePublished)
+	}
+
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	db := postgres.NewDB(
+		cfg.pgDB.dsn,
+		postgres.WithMaxOpenConns(cfg.pgDB.maxOpenConns),
+		postgres.WithMaxIdleConns(cfg.pgDB.maxIdleConns),
+		postgres.WithMaxIdleTime(cfg.pgDB.maxIdleTime),
+		postgres.WithConnectionTimeout(cfg.pgDB.connTimeout),
+		postgres.WithQueryTimeout(cfg.pgDB.queryTimeout),
+		postgres.WithKeepOnClose(true),
+	)
+	if err := db.Open(); err != nil {
+		return fmt.Errorf("connecting to a database: %w", err)
+	}
+	defer func() { err = multierr.Join(err, db.Close()) }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	importer := movieio.NewImporter(postgres.NewMovieService(db), postgres.NewGenreService(db))
+	report, err := importer.Import(ctx, r, f, status)
+	if report != nil {
+		logger.Debug("movies imported", "imported", report.Imported, "failed", report.Failed)
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// runImportIMDb imports movies from the gzipped IMDb "title.basics.tsv.gz" dataset file given as the positional argument.
+// Progress is logged periodically, and the statistics of the import are written to the standard output.
+func runImportIMDb(cfg *Config, logger *slog.Logger) (err error) {
+	if len(cfg.args) != 1 {
+		return errors.New("usage: greenlight import-imdb [flags] <title.basics.tsv.gz>")
+	}
+
+	file, err := os.Open(cfg.args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	db := postgres.NewDB(
+		cfg.pgDB.dsn,
+		postgres.WithMaxOpenConns(cfg.pgDB.maxOpenConns),
+		postgres.WithMaxIdleConns(cfg.pgDB.maxIdleConns),
+		postgres.WithMaxIdleTime(cfg.pgDB.maxIdleTime),
+		postgres.WithConnectionTimeout(cfg.pgDB.connTimeout),
+		postgres.WithQueryTimeout(cfg.pgDB.queryTimeout),
+		postgres.WithKeepOnClose(true),
+	)
+	if err := db.Open(); err != nil {
+		return fmt.Errorf("connecting to a database: %w", err)
+	}
+	defer func() { err = multierr.Join(err, db.Close()) }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var logged time.Time
+	progress := func(s movieio.IMDbStats) {
+		if time.Since(logged) < 5*time.Second {
+			return
+		}
+		logged = time.Now()
+		logger.Info("importing IMDb dataset", "lines", s.Lines, "inserted", s.Inserted, "updated", s.Updated, "unchanged", s.Unchanged, "duplicates", s.Duplicates, "invalid", s.Invalid)
+	}
+
+	importer := movieio.NewImporter(postgres.NewMovieService(db), postgres.NewGenreService(db))
+	stats, err := importer.ImportIMDb(ctx, file, progress)
+	if stats != nil {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(stats); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// sequenceChanges appends the committed movie changes to the feed every interval until the context is canceled.
+func sequenceChanges(ctx context.Context, movieService greenlight.MovieService, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := movieService.SequenceChanges(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("sequencing movie changes", "error", err)
+			}
+		}
+	}
+}
+
 func (c *Config) parseFlags(args []string) error {
 	fs := flag.NewFlagSet("greenlight", flag.ExitOnError)
 	// HTTP
@@ -145,6 +372,9 @@ func (c *Config) parseFlags(args []string) error {
 	fs.DurationVar(&c.http.writeTimeout, "http-write-timeout", 30*time.Second, "HTTP server write timeout")
 	fs.DurationVar(&c.http.shutdownTimeout, "http-shutdown-timeout", 20*time.Second, "HTTP server shutdown timeout")
 	fs.Int64Var(&c.http.maxRequestBody, "http-max-request-body", 1_048_576, "Maximum HTTP request body size in bytes")
+	fs.Int64Var(&c.http.maxPosterSize, "http-max-poster-size", 10_485_760, "Maximum uploaded poster size in bytes")
+	fs.StringVar(&c.http.duplicateMode, "http-duplicate-mode", "advisory", "Duplicate detection for created movies: off, advisory or strict")
+	fs.Int64Var(&c.http.maxImportSize, "http-max-import-size", 104_857_600, "Maximum imported movies data size in bytes")
 
 	// HTTP limiter
 	fs.Float64Var(&c.limiter.rps, "limiter-rps", 2, "Rate limiter maximum requests per second")
@@ -161,7 +391,38 @@ func (c *Config) parseFlags(args []string) error {
 	// Authentication token
 	fs.StringVar(&c.token.secret, "token-secret", "", "Authentication token secret")
 
-	return fs.Parse(args)
+	// Blob storage
+	fs.StringVar(&c.blob.dir, "blob-dir", "./data/blobs", "Directory to store blobs in")
+	fs.StringVar(&c.blob.baseURL, "blob-base-url", "http://localhost:8080", "Base URL of the blob URLs")
+	fs.StringVar(&c.blob.secret, "blob-secret", "", "Blob URL signing secret; URLs are not signed if empty")
+
+	// Movie import
+	fs.StringVar(&c.imp.format, "import-format", "", "Format of the imported movies: csv or ndjson; inferred from the file extension if empty")
+	fs.StringVar(&c.imp.status, "import-status", string(greenlight.MoviePublished), "Status of the imported movies: published or draft")
+
+	// Catalogue statistics
+	fs.DurationVar(&c.stats.refreshInterval, "stats-refresh-interval", 15*time.Minute, "Catalogue statistics refresh interval")
+
+	// Movie change feed
+	fs.DurationVar(&c.changes.sequenceInterval, "changes-sequence-interval", time.Second, "Interval of appending the committed movie changes to the feed")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	c.args = fs.Args()
+
+	switch greenlight.DuplicateMode(c.http.duplicateMode) {
+	case greenlight.DuplicatesOff, greenlight.DuplicatesAdvisory, greenlight.DuplicatesStrict:
+	default:
+		return fmt.Errorf("invalid duplicate mode: %q", c.http.duplicateMode)
+	}
+	if c.stats.refreshInterval <= 0 {
+		return fmt.Errorf("invalid statistics refresh interval: %s", c.stats.refreshInterval)
+	}
+	if c.changes.sequenceInterval <= 0 {
+		return fmt.Errorf("invalid change sequence interval: %s", c.changes.sequenceInterval)
+	}
+	return nil
 }
 
 func newLogger() *slog.Logger {
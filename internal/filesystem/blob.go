@@ -0,0 +1,161 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+// BlobStore represents a blob store backed by a local file system directory.
+// Objects are served by the HTTP server under the "/v1/blobs/" path.
+type BlobStore struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+var _ greenlight.BlobStore = (*BlobStore)(nil)
+
+// NewBlobStore returns a new instance of [BlobStore] storing objects in the root directory.
+// URLs of the objects are prefixed with the baseURL. If the secret is not empty, URLs are signed using it.
+func NewBlobStore(root, baseURL, secret string) *BlobStore {
+	return &BlobStore{
+		root:    root,
+		baseURL: baseURL,
+		secret:  []byte(secret),
+	}
+}
+
+// Get returns the object stored under the key.
+// Content type of the object is derived from the key extension.
+func (s *BlobStore) Get(ctx context.Context, key string) (_ *greenlight.Blob, err error) {
+	defer multierr.Wrap(&err, "filesystem.BlobStore.Get(%q)", key)
+
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	ct := mime.TypeByExtension(path.Ext(key))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return &greenlight.Blob{ReadCloser: f, ContentType: ct}, nil
+}
+
+// Put stores the content under the key. The object is replaced atomically.
+func (s *BlobStore) Put(ctx context.Context, key string, r io.Reader, contentType string) (err error) {
+	defer multierr.Wrap(&err, "filesystem.BlobStore.Put(%q)", key)
+
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never observe a partially written object.
+	f, err := os.CreateTemp(filepath.Dir(name), ".blob-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// Delete removes the object stored under the key.
+func (s *BlobStore) Delete(ctx context.Context, key string) (err error) {
+	defer multierr.Wrap(&err, "filesystem.BlobStore.Delete(%q)", key)
+
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL returns a URL of the object. If the store has a secret, the URL is signed and expires after the ttl.
+func (s *BlobStore) URL(ctx context.Context, key string, ttl time.Duration) (_ string, err error) {
+	defer multierr.Wrap(&err, "filesystem.BlobStore.URL(%q)", key)
+
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	u := s.baseURL + "/v1/blobs/" + key
+	if len(s.secret) == 0 {
+		return u, nil
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	vs := url.Values{}
+	vs.Set("expires", expires)
+	vs.Set("signature", s.sign(key, expires))
+	return u + "?" + vs.Encode(), nil
+}
+
+// VerifyURL verifies the query parameters of a URL returned by [BlobStore.URL] for the key.
+func (s *BlobStore) VerifyURL(key string, query url.Values) error {
+	if len(s.secret) == 0 {
+		return nil
+	}
+
+	expires := query.Get("expires")
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return greenlight.NewUnauthorizedError("The URL is expired.")
+	}
+	if !hmac.Equal([]byte(s.sign(key, expires)), []byte(query.Get("signature"))) {
+		return greenlight.NewUnauthorizedError("The URL signature is invalid.")
+	}
+	return nil
+}
+
+// sign returns a signature of the key valid until expires.
+func (s *BlobStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path returns the file path of the object stored under the key.
+func (s *BlobStore) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", greenlight.NewInvalidError("Invalid blob key: %s", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,98 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+func TestBlobStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewBlobStore(t.TempDir(), "http://localhost:8080", "")
+
+	if err := s.Put(ctx, "posters/1/original.png", strings.NewReader("foo"), "image/png"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	b, err := s.Get(ctx, "posters/1/original.png")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	data, err := io.ReadAll(b)
+	_ = b.Close()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != "foo" {
+		t.Errorf("want content: %q, got: %q", "foo", data)
+	}
+	if b.ContentType != "image/png" {
+		t.Errorf("want content type: %q, got: %q", "image/png", b.ContentType)
+	}
+
+	if err := s.Delete(ctx, "posters/1/original.png"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.Get(ctx, "posters/1/original.png"); !errors.Is(err, greenlight.ErrNotFound) {
+		t.Errorf("want error: %v, got: %v", greenlight.ErrNotFound, err)
+	}
+	if err := s.Delete(ctx, "posters/1/original.png"); err != nil {
+		t.Errorf("delete non-existing: %v", err)
+	}
+}
+
+func TestBlobStoreInvalidKey(t *testing.T) {
+	ctx := context.Background()
+	s := NewBlobStore(t.TempDir(), "http://localhost:8080", "")
+
+	for _, key := range []string{"", ".", "../foo", "/foo", "foo/../../bar"} {
+		if err := s.Put(ctx, key, strings.NewReader("foo"), "text/plain"); !errors.As(err, new(*greenlight.InvalidError)) {
+			t.Errorf("key %q: want invalid error, got: %v", key, err)
+		}
+	}
+}
+
+func TestBlobStoreURL(t *testing.T) {
+	ctx := context.Background()
+	s := NewBlobStore(t.TempDir(), "http://localhost:8080", "secret")
+
+	tests := []struct {
+		key     string
+		ttl     time.Duration
+		tamper  func(vs url.Values)
+		wantErr bool
+	}{
+		{key: "posters/1/w185.jpg", ttl: time.Hour},
+		{key: "posters/1/w185.jpg", ttl: -time.Hour, wantErr: true},
+		{key: "posters/1/w185.jpg", ttl: time.Hour, tamper: func(vs url.Values) { vs.Set("expires", "9999999999") }, wantErr: true},
+		{key: "posters/1/w185.jpg", ttl: time.Hour, tamper: func(vs url.Values) { vs.Del("signature") }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		raw, err := s.URL(ctx, tt.key, tt.ttl)
+		if err != nil {
+			t.Fatalf("url: %v", err)
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("parse url: %v", err)
+		}
+		if u.Path != "/v1/blobs/"+tt.key {
+			t.Errorf("want path: %q, got: %q", "/v1/blobs/"+tt.key, u.Path)
+		}
+
+		vs := u.Query()
+		if tt.tamper != nil {
+			tt.tamper(vs)
+		}
+		if err := s.VerifyURL(tt.key, vs); (err != nil) != tt.wantErr {
+			t.Errorf("want error: %t, got: %v", tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package filesystem implements local file system interactions.
+package filesystem
@@ -0,0 +1,27 @@
+package greenlight
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Blob represents a stored binary object.
+type Blob struct {
+	io.ReadCloser
+	// ContentType is a MIME type of the content.
+	ContentType string
+}
+
+// BlobStore is a storage of binary objects addressed by slash-separated keys.
+type BlobStore interface {
+	// Get returns the object stored under the key. The caller must close the returned blob.
+	Get(ctx context.Context, key string) (*Blob, error)
+	// Put stores the content under the key, replacing an existing object.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Delete removes the object stored under the key. Deleting a non-existing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns a URL the object can be downloaded from.
+	// If the store signs URLs, the returned URL is only valid for the ttl.
+	URL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
@@ -0,0 +1,76 @@
+package greenlight
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CertificationSystems are the age certification systems keyed by the ISO 3166-1 country code.
+// Each system lists its certifications from the least to the most restrictive one.
+var CertificationSystems = map[string][]string{
+	// Motion Picture Association film rating system.
+	"US": {"G", "PG", "PG-13", "R", "NC-17"},
+	// British Board of Film Classification.
+	"GB": {"U", "PG", "12A", "12", "15", "18", "R18"},
+	// Freiwillige Selbstkontrolle der Filmwirtschaft.
+	"DE": {"0", "6", "12", "16", "18"},
+}
+
+// Certification is an age certification of a movie in a country, e.g. "PG-13" in "US".
+type Certification struct {
+	Country string
+	Rating  string
+}
+
+// ParseCertification parses a certification in the "COUNTRY:RATING" format, e.g. "US:PG-13".
+func ParseCertification(s string) (Certification, error) {
+	country, rating, ok := strings.Cut(s, ":")
+	if !ok {
+		return Certification{}, NewInvalidError(`Certification must be in the "COUNTRY:RATING" format: %s`, s)
+	}
+	c := Certification{Country: strings.ToUpper(country), Rating: strings.ToUpper(rating)}
+	if err := c.Valid(); err != nil {
+		return Certification{}, err
+	}
+	return c, nil
+}
+
+// String returns the certification in the "COUNTRY:RATING" format, or an empty string for the zero certification.
+func (c Certification) String() string {
+	if c == (Certification{}) {
+		return ""
+	}
+	return c.Country + ":" + c.Rating
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (c Certification) Valid() error {
+	err := NewInvalidError("Certification is invalid.")
+
+	if system, ok := CertificationSystems[c.Country]; !ok {
+		err.AddViolationMsg("country", fmt.Sprintf("Unknown country %q.", c.Country))
+	} else if !slices.Contains(system, c.Rating) {
+		err.AddViolationMsg("rating", fmt.Sprintf("Unknown %s rating %q.", c.Country, c.Rating))
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// Allows reports whether a movie having the certifications, keyed by the country, is allowed by the maximum certification.
+// The zero certification allows any movie. Otherwise, movies not certified in the country of the certification are not allowed.
+func (c Certification) Allows(certs map[string]string) bool {
+	if c == (Certification{}) {
+		return true
+	}
+	rating, ok := certs[c.Country]
+	if !ok {
+		return false
+	}
+	system := CertificationSystems[c.Country]
+	i := slices.Index(system, rating)
+	return i >= 0 && i <= slices.Index(system, c.Rating)
+}
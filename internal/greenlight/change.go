@@ -0,0 +1,62 @@
+package greenlight
+
+import (
+	"context"
+	"time"
+)
+
+// MovieChangeOp is an operation that changed a movie.
+type MovieChangeOp string
+
+const (
+	MovieCreated MovieChangeOp = "created"
+	MovieUpdated MovieChangeOp = "updated"
+	MovieDeleted MovieChangeOp = "deleted"
+)
+
+// MovieChange is an entry of the change feed of the catalogue.
+// Every change of a movie version is recorded in the same transaction as the change itself,
+// and is appended to the feed after the commit, see [MovieService.SequenceChanges].
+type MovieChange struct {
+	// Seq is the position of the change in the feed. Changes appended later have greater positions.
+	Seq     int64         `json:"-"`
+	MovieID int64         `json:"id"`
+	Op      MovieChangeOp `json:"op"`
+	// Version is the version of the movie after the change, or the last version of the deleted movie.
+	Version   int32     `json:"version"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// MovieChangeFilter is a filter used to retrieve the change feed of the catalogue.
+type MovieChangeFilter struct {
+	// Since is the position in the feed after which the changes are returned, see [MovieChange.Seq].
+	// Zero returns the feed from the beginning.
+	Since int64
+	// Limit is the maximum number of the changes to return.
+	Limit int
+}
+
+func (f *MovieChangeFilter) Valid() error {
+	err := NewInvalidError("Movie change filter parameter(s) is/are invalid.")
+
+	if f.Since < 0 {
+		err.AddViolationMsg("since", "Must not be negative.")
+	}
+
+	if f.Limit < 1 || f.Limit > 1000 {
+		err.AddViolationMsg("limit", "Must be between 1 and 1000.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// MovieEventService is a service delivering the changes of the movies as they're made, see [MovieChange].
+type MovieEventService interface {
+	// Subscribe returns a channel receiving the changes made after the subscription, in the feed order.
+	// The channel is closed when the context is done, or when changes may have been missed, e.g. if the subscriber
+	// falls behind. The subscriber then resumes from the change feed, see [MovieService.Changes].
+	Subscribe(ctx context.Context) <-chan *MovieChange
+}
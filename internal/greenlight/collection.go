@@ -0,0 +1,97 @@
+package greenlight
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+// slugRx matches URL slugs, e.g. "award-winners-2023".
+var slugRx = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Collection represents an editorially curated collection of movies.
+type Collection struct {
+	ID int64
+	// Slug is a unique URL identifier of the collection.
+	Slug        string
+	Title       string
+	Description string
+	// MovieIDs are IDs of the collection movies in order.
+	MovieIDs []int64
+	// PublishFrom is the time the collection is published at. Zero value means the collection is not published.
+	PublishFrom time.Time
+	// PublishUntil is the time the collection is unpublished at. Zero value means the collection is published indefinitely.
+	PublishUntil time.Time
+	Version      int32
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (c *Collection) Valid() error {
+	err := NewInvalidError("Collection is invalid.")
+
+	if c.ID < 0 {
+		err.AddViolationMsg("ID", "Must be greater or equal to 0.")
+	}
+
+	if !slugRx.MatchString(c.Slug) {
+		err.AddViolationMsg("slug", "Must consist of lowercase letters and digits separated by single dashes.")
+	}
+	if len(c.Slug) > 100 {
+		err.AddViolationMsg("slug", "Must not be more than 100 bytes long.")
+	}
+
+	if c.Title == "" {
+		err.AddViolationMsg("title", "Must be provided.")
+	}
+	if utf8.RuneCountInString(c.Title) > 200 {
+		err.AddViolationMsg("title", "Must not be more than 200 characters long.")
+	}
+
+	if utf8.RuneCountInString(c.Description) > 2000 {
+		err.AddViolationMsg("description", "Must not be more than 2000 characters long.")
+	}
+
+	if len(c.MovieIDs) > 500 {
+		err.AddViolationMsg("movie_ids", "Must not contain more than 500 movies.")
+	}
+	for i, id := range c.MovieIDs {
+		if id <= 0 {
+			err.AddViolationMsg("movie_ids", "Must be greater than 0.")
+			break
+		}
+		if slices.Contains(c.MovieIDs[:i], id) {
+			err.AddViolationMsg("movie_ids", "Must not contain duplicates.")
+			break
+		}
+	}
+
+	if !c.PublishUntil.IsZero() && !c.PublishUntil.After(c.PublishFrom) {
+		err.AddViolationMsg("publish_until", "Must be after publish_from.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// Published reports whether the collection is published at the time.
+func (c *Collection) Published(t time.Time) bool {
+	return !c.PublishFrom.IsZero() && !t.Before(c.PublishFrom) && (c.PublishUntil.IsZero() || t.Before(c.PublishUntil))
+}
+
+// CollectionService is a service for managing collections.
+type CollectionService interface {
+	Get(ctx context.Context, id int64) (*Collection, error)
+	GetBySlug(ctx context.Context, slug string) (*Collection, error)
+	// GetAll returns all the collections. If published is true, only collections published at the moment are returned.
+	GetAll(ctx context.Context, published bool) ([]*Collection, error)
+	Create(ctx context.Context, c *Collection) error
+	Update(ctx context.Context, c *Collection) error
+	Delete(ctx context.Context, id int64) error
+	// Movies returns the movies of the collection in order.
+	// The movies are restricted to the ones visible to the user, see [Movie.VisibleTo]. Nil user matches any movie.
+	Movies(ctx context.Context, id int64, visibleTo *User) ([]*Movie, error)
+}
@@ -5,7 +5,9 @@ import "context"
 type ctxKey string
 
 const (
-	userIDCtxKey ctxKey = "userID"
+	userIDCtxKey    ctxKey = "userID"
+	languagesCtxKey ctxKey = "languages"
+	duplicateCtxKey ctxKey = "duplicateMode"
 )
 
 func NewContextWithUserID(ctx context.Context, userID int64) context.Context {
@@ -16,3 +18,29 @@ func UserIDFromContext(ctx context.Context) int64 {
 	userID, _ := ctx.Value(userIDCtxKey).(int64)
 	return userID
 }
+
+// NewContextWithLanguages returns a context carrying the preferred languages of the client, most preferred first.
+// Services resolve localized details of the resources using [LanguageFallbacks] of the languages.
+func NewContextWithLanguages(ctx context.Context, langs []string) context.Context {
+	return context.WithValue(ctx, languagesCtxKey, langs)
+}
+
+// LanguagesFromContext returns the preferred languages of the client, or nil if the original details are preferred.
+func LanguagesFromContext(ctx context.Context) []string {
+	langs, _ := ctx.Value(languagesCtxKey).([]string)
+	return langs
+}
+
+// NewContextWithDuplicateMode returns a context carrying the mode of the duplicate detection for created movies.
+func NewContextWithDuplicateMode(ctx context.Context, mode DuplicateMode) context.Context {
+	return context.WithValue(ctx, duplicateCtxKey, mode)
+}
+
+// DuplicateModeFromContext returns the mode of the duplicate detection, or [DuplicatesOff] if not set.
+func DuplicateModeFromContext(ctx context.Context) DuplicateMode {
+	mode, ok := ctx.Value(duplicateCtxKey).(DuplicateMode)
+	if !ok {
+		return DuplicatesOff
+	}
+	return mode
+}
@@ -74,6 +74,8 @@ func (e *InvalidError) Violations() map[string]error {
 
 type ConflictError struct {
 	Msg string
+	// IDs are IDs of the conflicting resources, if known.
+	IDs []int64
 }
 
 func NewConflictError(format string, args ...any) *ConflictError {
@@ -113,3 +115,17 @@ func NewUnauthorizedError(format string, args ...any) *UnauthorizedError {
 func (e *UnauthorizedError) Error() string {
 	return e.Msg
 }
+
+type ForbiddenError struct {
+	Msg string
+}
+
+func NewForbiddenError(format string, args ...any) *ForbiddenError {
+	return &ForbiddenError{
+		Msg: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Msg
+}
@@ -0,0 +1,52 @@
+package greenlight
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// Genre represents a genre of the movie vocabulary.
+type Genre struct {
+	// Slug is a canonical identifier of the genre, e.g. "science-fiction". [Movie] genres are genre slugs.
+	Slug string `json:"slug"`
+	// Name is a display name of the genre, e.g. "Science Fiction".
+	Name string `json:"name"`
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (g *Genre) Valid() error {
+	err := NewInvalidError("Genre is invalid.")
+
+	if !slugRx.MatchString(g.Slug) {
+		err.AddViolationMsg("slug", "Must consist of lowercase letters and digits separated by single dashes.")
+	}
+	if len(g.Slug) > 50 {
+		err.AddViolationMsg("slug", "Must not be more than 50 bytes long.")
+	}
+
+	if g.Name == "" {
+		err.AddViolationMsg("name", "Must be provided.")
+	}
+	if utf8.RuneCountInString(g.Name) > 50 {
+		err.AddViolationMsg("name", "Must not be more than 50 characters long.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// GenreService is a service for managing genres.
+type GenreService interface {
+	Get(ctx context.Context, slug string) (*Genre, error)
+	// GetAll returns all the genres ordered by name.
+	GetAll(ctx context.Context) ([]*Genre, error)
+	Create(ctx context.Context, g *Genre) error
+	// Rename changes the slug and the name of the genre identified by the slug.
+	// If the slug changes, the movies having the genre are rewritten to the new slug, which may take long.
+	Rename(ctx context.Context, slug string, g *Genre) error
+	// Merge merges the genre identified by the slug into another one.
+	// The movies having the genre get the other genre instead, which may take long, and the genre is deleted.
+	Merge(ctx context.Context, slug, into string) error
+}
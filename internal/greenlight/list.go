@@ -0,0 +1,125 @@
+package greenlight
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// ListVisibility specifies who can see a [List].
+type ListVisibility string
+
+const (
+	// ListPrivate lists are only visible to their owners.
+	ListPrivate ListVisibility = "private"
+	// ListUnlisted lists are visible to anyone knowing their ID, but aren't listed on the owner's profile.
+	ListUnlisted ListVisibility = "unlisted"
+	// ListPublic lists are visible to anyone.
+	ListPublic ListVisibility = "public"
+)
+
+// WatchlistName is the name of the built-in user watchlist.
+const WatchlistName = "Watchlist"
+
+// List represents a user list of movies.
+type List struct {
+	ID     int64
+	UserID int64
+	Name   string
+	// Watchlist reports whether the list is the built-in watchlist of the user.
+	// Every user has exactly one watchlist, which can't be deleted or renamed.
+	Watchlist  bool
+	Visibility ListVisibility
+	CreatedAt  time.Time
+	Version    int32
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (l *List) Valid() error {
+	err := NewInvalidError("List is invalid.")
+
+	if l.ID < 0 {
+		err.AddViolationMsg("ID", "Must be greater or equal to 0.")
+	}
+
+	if l.Name == "" {
+		err.AddViolationMsg("name", "Must be provided.")
+	}
+	if utf8.RuneCountInString(l.Name) > 200 {
+		err.AddViolationMsg("name", "Must not be more than 200 characters long.")
+	}
+	// The name of the watchlist is reserved, since the watchlist is created lazily and names are unique per user.
+	if !l.Watchlist && l.Name == WatchlistName {
+		err.AddViolationMsg("name", fmt.Sprintf("%q is reserved for the watchlist.", WatchlistName))
+	}
+
+	switch l.Visibility {
+	case ListPrivate, ListUnlisted, ListPublic:
+	default:
+		err.AddViolationMsg("visibility", `Must be one of "private", "unlisted" or "public".`)
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// ListEntry represents a movie in a [List].
+type ListEntry struct {
+	ListID  int64
+	MovieID int64
+	// Position is the position of the entry in the list, starting from 1.
+	Position int
+	Notes    string
+	AddedAt  time.Time
+
+	// Movie is the listed movie. Only populated when retrieving the list entries.
+	Movie *Movie
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (e *ListEntry) Valid() error {
+	err := NewInvalidError("List entry is invalid.")
+
+	if e.MovieID <= 0 {
+		err.AddViolationMsg("movie_id", "Must be greater than 0.")
+	}
+
+	if e.Position < 0 {
+		err.AddViolationMsg("position", "Must be greater or equal to 0.")
+	}
+
+	if utf8.RuneCountInString(e.Notes) > 2000 {
+		err.AddViolationMsg("notes", "Must not be more than 2000 characters long.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// ListService is a service for managing user lists of movies.
+type ListService interface {
+	Get(ctx context.Context, id int64) (*List, error)
+	// GetAll returns the lists of the user, the watchlist first.
+	// If public is true, only public lists are returned.
+	GetAll(ctx context.Context, userID int64, public bool) ([]*List, error)
+	// Watchlist returns the watchlist of the user, creating it if it doesn't exist.
+	Watchlist(ctx context.Context, userID int64) (*List, error)
+	Create(ctx context.Context, l *List) error
+	Update(ctx context.Context, l *List) error
+	Delete(ctx context.Context, id int64) error
+
+	// Entries returns the entries of the list ordered by the position, with the listed movies.
+	// The entries are restricted to the movies visible to the user, see [Movie.VisibleTo]. Nil user matches any movie.
+	Entries(ctx context.Context, listID int64, visibleTo *User) ([]*ListEntry, error)
+	// AddEntry adds the movie to the list at the entry position, shifting the following entries.
+	// If the position is 0 or past the end of the list, the entry is appended.
+	AddEntry(ctx context.Context, e *ListEntry) error
+	// UpdateEntry moves the entry to its position and updates its notes.
+	UpdateEntry(ctx context.Context, e *ListEntry) error
+	RemoveEntry(ctx context.Context, listID, movieID int64) error
+}
@@ -2,20 +2,51 @@ package greenlight
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"slices"
 	"time"
 )
 
 // Movie represents a movie.
 type Movie struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
+	ID int64 `json:"id"`
+	// Title is the original title, or a localized one if the movie is localized. See [Movie.Language].
+	Title string `json:"title"`
+	// Synopsis is a localized synopsis of the movie. It is only provided if the movie is localized.
+	Synopsis string `json:"synopsis,omitempty"`
+	// Language is the language of the localized title and synopsis, or empty if the title is the original one.
+	// Movies are localized when retrieved using a context carrying languages, see [NewContextWithLanguages].
+	Language    string    `json:"-"`
 	ReleaseDate time.Time `json:"release_date,omitempty"`
 	Runtime     int       `json:"runtime,omitempty"`
 	Genres      []string  `json:"genres,omitempty"`
-	Version     int32     `json:"-"`
+	// Poster are keys of the poster images in a [BlobStore], keyed by the image name. See [NewPosterImages].
+	Poster map[string]string `json:"-"`
+	// Rating is the average rating of the movie [Review] reviews. It is maintained by the [ReviewService].
+	Rating float64 `json:"rating"`
+	// Votes is the number of the movie reviews. It is maintained by the [ReviewService].
+	Votes int `json:"votes"`
+	// ExternalIDs are IDs of the movie in the external catalogues keyed by the catalogue, see [ExternalSources].
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`
+	// Certifications are the age certification ratings of the movie keyed by the country, see [CertificationSystems].
+	Certifications map[string]string `json:"certifications,omitempty"`
+	// Tags are the tags added to the movie by users. They are maintained by the [TagService].
+	Tags []string `json:"-"`
+	// CreatedBy is the ID of the [User] that created the movie, or 0 if unknown. It is set by the [MovieService].
+	CreatedBy int64 `json:"-"`
+	// Status is the status of the movie in the editorial workflow, see [Movie.Transition].
+	// New movies are drafts unless the status is set.
+	Status MovieStatus `json:"-"`
+	// PublishAt is the time a published movie becomes visible at. Zero value means the movie is visible once published.
+	PublishAt time.Time `json:"-"`
+	// Duplicates are IDs of the likely duplicates of the movie found when it was created in the [DuplicatesAdvisory] mode.
+	Duplicates []int64 `json:"-"`
+	Version    int32   `json:"-"`
 }
 
 // Valid returns an error if the validation fails, otherwise nil.
+// Genres are only checked to be well-formed [Genre] slugs, the [MovieService] checks that they exist.
 func (m *Movie) Valid() error {
 	err := NewInvalidError("Movie is invalid.")
 
@@ -42,6 +73,40 @@ func (m *Movie) Valid() error {
 	if len(m.Genres) == 0 {
 		err.AddViolationMsg("genres", "Must be provided.")
 	}
+	for src, id := range m.ExternalIDs {
+		rx, ok := ExternalSources[src]
+		if !ok {
+			err.AddViolationMsg("external_ids", fmt.Sprintf("Unknown source %q.", src))
+			continue
+		}
+		if !rx.MatchString(id) {
+			err.AddViolationMsg("external_ids", fmt.Sprintf("Invalid %s ID %q.", src, id))
+		}
+	}
+
+	for country, rating := range m.Certifications {
+		c := Certification{Country: country, Rating: rating}
+		if c.Valid() != nil {
+			err.AddViolationMsg("certifications", fmt.Sprintf("Invalid certification %q.", c))
+		}
+	}
+
+	switch m.Status {
+	case "", MovieDraft, MovieInReview, MoviePublished, MovieArchived:
+	default:
+		err.AddViolationMsg("status", fmt.Sprintf("Unknown status %q.", m.Status))
+	}
+
+	for i, g := range m.Genres {
+		if !slugRx.MatchString(g) {
+			err.AddViolationMsg("genres", fmt.Sprintf("Invalid genre %q.", g))
+			break
+		}
+		if slices.Contains(m.Genres[:i], g) {
+			err.AddViolationMsg("genres", "Must not contain duplicates.")
+			break
+		}
+	}
 
 	if len(err.violations) != 0 {
 		return err
@@ -49,23 +114,194 @@ func (m *Movie) Valid() error {
 	return nil
 }
 
+// DuplicateMode is a mode of the detection of the likely duplicates of created movies.
+// Likely duplicates have similar normalized titles and release years no more than a year apart.
+type DuplicateMode string
+
+const (
+	// DuplicatesOff disables the detection.
+	DuplicatesOff DuplicateMode = "off"
+	// DuplicatesAdvisory creates movies having duplicates, reporting the duplicates in [Movie.Duplicates].
+	DuplicatesAdvisory DuplicateMode = "advisory"
+	// DuplicatesStrict rejects movies having duplicates with a [ConflictError] listing the duplicates.
+	DuplicatesStrict DuplicateMode = "strict"
+)
+
+// CanEdit reports whether the user having the role can update or delete the movie.
+// Movies can be edited by their creators and by editors.
+func (m *Movie) CanEdit(userID int64, role Role) bool {
+	return (m.CreatedBy != 0 && m.CreatedBy == userID) || role.Grants(RoleEditor)
+}
+
+// MovieStatus is a status of a movie in the editorial workflow.
+type MovieStatus string
+
+const (
+	// MovieDraft is the status of new movies, visible only to the users that can edit them.
+	MovieDraft MovieStatus = "draft"
+	// MovieInReview is the status of movies submitted to editors for publishing.
+	MovieInReview MovieStatus = "in_review"
+	// MoviePublished is the status of movies in the public catalogue, starting from [Movie.PublishAt] if it's set.
+	MoviePublished MovieStatus = "published"
+	// MovieArchived is the status of movies withdrawn from the public catalogue.
+	MovieArchived MovieStatus = "archived"
+)
+
+// movieTransitions are the allowed transitions between the movie statuses with the role required to perform them.
+// Transitions requiring [RoleUser] can only be performed by the users that can edit the movie, see [Movie.CanEdit].
+var movieTransitions = map[[2]MovieStatus]Role{
+	{MovieDraft, MovieInReview}:      RoleUser,
+	{MovieInReview, MovieDraft}:      RoleUser,
+	{MovieDraft, MoviePublished}:     RoleEditor,
+	{MovieInReview, MoviePublished}:  RoleEditor,
+	{MoviePublished, MoviePublished}: RoleEditor,
+	{MoviePublished, MovieDraft}:     RoleEditor,
+	{MoviePublished, MovieArchived}:  RoleEditor,
+	{MovieArchived, MoviePublished}:  RoleEditor,
+	{MovieArchived, MovieDraft}:      RoleEditor,
+}
+
+// Transition changes the status of the movie on behalf of the user, returning an error if the user can't perform it.
+// The publish time schedules publishing of the movie, so it's only allowed for the [MoviePublished] status.
+func (m *Movie) Transition(to MovieStatus, publishAt time.Time, u *User) error {
+	if !publishAt.IsZero() && to != MoviePublished {
+		return NewInvalidError("Publish time is only allowed for the %q status.", MoviePublished)
+	}
+	role, ok := movieTransitions[[2]MovieStatus{m.Status, to}]
+	if !ok {
+		return NewConflictError("The movie status can't be changed from %q to %q.", m.Status, to)
+	}
+	if !m.CanEdit(u.ID, u.Role) || !u.Role.Grants(role) {
+		return NewForbiddenError("You are not allowed to change the movie status from %q to %q.", m.Status, to)
+	}
+	m.Status, m.PublishAt = to, publishAt
+	return nil
+}
+
+// Published reports whether the movie is published at the time.
+func (m *Movie) Published(t time.Time) bool {
+	return m.Status == MoviePublished && !t.Before(m.PublishAt)
+}
+
+// VisibleTo reports whether the movie is visible to the user at the time.
+// Published movies are visible to everyone, including anonymous users represented by the zero [User],
+// and the other movies only to the users that can edit them. Movies not allowed by the [User.MaxCertification]
+// are hidden from the user.
+func (m *Movie) VisibleTo(u *User, t time.Time) bool {
+	return (m.Published(t) || m.CanEdit(u.ID, u.Role)) && u.MaxCertification.Allows(m.Certifications)
+}
+
+// ExternalSources are the external catalogues of the [Movie.ExternalIDs] with the formats of their IDs.
+var ExternalSources = map[string]*regexp.Regexp{
+	"imdb":     regexp.MustCompile(`^tt[0-9]{7,}$`),
+	"tmdb":     regexp.MustCompile(`^[1-9][0-9]*$`),
+	"wikidata": regexp.MustCompile(`^Q[1-9][0-9]*$`),
+}
+
 // MovieFilter is a filter used to retrieve movies.
 type MovieFilter struct {
-	// Title is a title of the movie.
+	// Title is a title of the movie, matching the original title and the translated ones.
 	Title string
 	// Genres are genres of the movie.
 	Genres []string
+	// GenresMode specifies how [MovieFilter.Genres] are matched: "all" matches movies having all of the genres,
+	// "any" matches movies having at least one of them. Empty value is the same as "all".
+	GenresMode string
+	// Tags are tags of the movie. Movies having all of the tags are matched.
+	Tags []string
+	// ReleaseDateFrom is the earliest release date of the movie, inclusive. Zero value means no lower bound.
+	ReleaseDateFrom time.Time
+	// ReleaseDateTo is the latest release date of the movie, inclusive. Zero value means no upper bound.
+	ReleaseDateTo time.Time
+	// Year is the release year of the movie. Zero value matches any year.
+	Year int
+	// RuntimeMin is the minimum runtime of the movie, inclusive. Zero value means no lower bound.
+	RuntimeMin int
+	// RuntimeMax is the maximum runtime of the movie, inclusive. Zero value means no upper bound.
+	RuntimeMax int
+	// PersonID is the ID of a [Person] credited in the movie. Zero value matches any movie.
+	PersonID int64
+	// CreatedBy is the ID of the [User] that created the movie. Zero value matches any movie.
+	CreatedBy int64
+	// Status is the status of the movie. Empty value matches any status.
+	Status MovieStatus
+	// Certification is a certification of the movie. Zero value matches any movie.
+	Certification Certification
+	// MaxCertification is the maximum certification of the movie, see [Certification.Allows]. Zero value matches any movie.
+	MaxCertification Certification
+	// VisibleTo restricts the movies to the ones visible to the user, see [Movie.VisibleTo]. Nil matches any movie.
+	VisibleTo *User
 	// Page is the number of the page to return.
 	Page int
 	// PageSize is the size of the page.
 	PageSize int
 	// Sort is a name of the [Movie] field to sort results on. To sort in descending order, prepend '-' to the field name.
 	Sort string
+	// Fields are names of the [Movie] fields to return. If empty, all fields are returned.
+	Fields []string
+	// Facets are names of the facets to count movies matching the filter by. See [MovieFacets].
+	Facets []string
 }
 
 func (f *MovieFilter) Valid() error {
 	err := NewInvalidError("Movie filter parameter(s) is/are invalid.")
 
+	for _, g := range f.Genres {
+		if !slugRx.MatchString(g) {
+			err.AddViolationMsg("genres", fmt.Sprintf("Invalid genre %q.", g))
+			break
+		}
+	}
+
+	if e := tagsValid(f.Tags); e != nil {
+		err.AddViolation("tags", e)
+	}
+
+	switch f.GenresMode {
+	case "", "all", "any":
+	default:
+		err.AddViolationMsg("genres_mode", `Must be either "all" or "any".`)
+	}
+
+	if !f.ReleaseDateFrom.IsZero() && !f.ReleaseDateTo.IsZero() && f.ReleaseDateFrom.After(f.ReleaseDateTo) {
+		err.AddViolationMsg("release_date_from", "Must not be after release_date_to.")
+	}
+
+	if f.Year != 0 && (f.Year < 1800 || f.Year > time.Now().Year()) {
+		err.AddViolationMsg("year", "Must be between 1800 and the current year.")
+	}
+
+	if f.RuntimeMin < 0 {
+		err.AddViolationMsg("runtime_min", "Must be greater or equal to 0.")
+	}
+	if f.RuntimeMax < 0 {
+		err.AddViolationMsg("runtime_max", "Must be greater or equal to 0.")
+	}
+	if f.RuntimeMax != 0 && f.RuntimeMin > f.RuntimeMax {
+		err.AddViolationMsg("runtime_min", "Must not be greater than runtime_max.")
+	}
+
+	if f.PersonID < 0 {
+		err.AddViolationMsg("person_id", "Must be greater or equal to 0.")
+	}
+
+	if f.CreatedBy < 0 {
+		err.AddViolationMsg("created_by", "Must be greater or equal to 0.")
+	}
+
+	switch f.Status {
+	case "", MovieDraft, MovieInReview, MoviePublished, MovieArchived:
+	default:
+		err.AddViolationMsg("status", fmt.Sprintf("Unknown status %q.", f.Status))
+	}
+
+	if f.Certification != (Certification{}) && f.Certification.Valid() != nil {
+		err.AddViolationMsg("certification", fmt.Sprintf("Invalid certification %q.", f.Certification))
+	}
+	if f.MaxCertification != (Certification{}) && f.MaxCertification.Valid() != nil {
+		err.AddViolationMsg("certification_max", fmt.Sprintf("Invalid certification %q.", f.MaxCertification))
+	}
+
 	if f.Page < 1 || f.Page > 10_000_000 {
 		err.AddViolationMsg("page", "Must be between 1 and 10_000_000.")
 	}
@@ -75,23 +311,158 @@ func (f *MovieFilter) Valid() error {
 	}
 
 	switch f.Sort {
-	case "id", "title", "release_date", "runtime", "genres", "version":
-	case "-id", "-title", "-release_date", "-runtime", "-genres", "-version":
+	case "id", "title", "release_date", "runtime", "genres", "rating", "version":
+	case "-id", "-title", "-release_date", "-runtime", "-genres", "-rating", "-version":
 	default:
 		err.AddViolationMsg("sort", "Parameter is incorrect.")
 	}
 
+	if e := MovieFieldsValid(f.Fields); e != nil {
+		err.AddViolation("fields", e)
+	}
+
+	for _, fc := range f.Facets {
+		switch fc {
+		case FacetGenres, FacetDecade, FacetRuntimeBucket:
+		default:
+			err.AddViolationMsg("facets", fmt.Sprintf("Unknown facet %q.", fc))
+		}
+	}
+
 	if len(err.violations) != 0 {
 		return err
 	}
 	return nil
 }
 
+// SimilarMovieFilter is a filter used to retrieve movies similar to a movie.
+type SimilarMovieFilter struct {
+	// MovieID is the ID of the movie to find similar movies to.
+	MovieID int64
+	// Page is the number of the page to return.
+	Page int
+	// PageSize is the size of the page.
+	PageSize int
+	// Fields are names of the [Movie] fields to return. If empty, all fields are returned.
+	Fields []string
+	// VisibleTo restricts the movies to the ones visible to the user, see [Movie.VisibleTo]. Nil matches any movie.
+	VisibleTo *User
+}
+
+func (f *SimilarMovieFilter) Valid() error {
+	err := NewInvalidError("Similar movie filter parameter(s) is/are invalid.")
+
+	if f.Page < 1 || f.Page > 10_000_000 {
+		err.AddViolationMsg("page", "Must be between 1 and 10_000_000.")
+	}
+
+	if f.PageSize < 1 || f.PageSize > 100 {
+		err.AddViolationMsg("page_size", "Must be between 1 and 100.")
+	}
+
+	if e := MovieFieldsValid(f.Fields); e != nil {
+		err.AddViolation("fields", e)
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// Movie facets.
+const (
+	// FacetGenres counts movies by genre. A movie is counted once for each of its genres.
+	FacetGenres = "genres"
+	// FacetDecade counts movies by release decade, e.g. "1990".
+	FacetDecade = "decade"
+	// FacetRuntimeBucket counts movies by runtime range in minutes: "0-89", "90-119", "120-149" and "150+".
+	FacetRuntimeBucket = "runtime_bucket"
+)
+
+// MovieFacets are counts of movies keyed by the facet name and the facet value.
+type MovieFacets map[string]map[string]int
+
+// movieFields are names of the [Movie] fields that can be requested by clients.
+var movieFields = []string{"id", "title", "release_date", "runtime", "genres", "poster", "rating", "votes", "external_ids", "certifications", "tags", "status", "publish_at"}
+
+// MovieFieldsValid returns an error if any of the fields is not a [Movie] field that can be requested.
+func MovieFieldsValid(fields []string) error {
+	for _, f := range fields {
+		if !slices.Contains(movieFields, f) {
+			return fmt.Errorf("Unknown field %q.", f)
+		}
+	}
+	return nil
+}
+
+// MovieStats are statistics of the catalogue. Except for the counts by status, only published movies are counted.
+type MovieStats struct {
+	Total int `json:"total"`
+	// ByStatus are the counts of all the movies by status.
+	ByStatus map[MovieStatus]int `json:"by_status,omitempty"`
+	// ByGenre are the counts by genre. A movie is counted once for each of its genres.
+	ByGenre map[string]int `json:"by_genre"`
+	// ByDecade are the counts by release decade, e.g. "1990".
+	ByDecade      map[string]int `json:"by_decade"`
+	RuntimeAvg    float64        `json:"runtime_avg"`
+	RuntimeMedian float64        `json:"runtime_median"`
+	// Recent are the most recently added movies, most recent first.
+	Recent []*Movie `json:"-"`
+	// RefreshedAt is the time the statistics were calculated at.
+	RefreshedAt time.Time `json:"refreshed_at"`
+}
+
+// UpsertStats are counts of the movies upserted in bulk.
+type UpsertStats struct {
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	// Duplicates are the movies skipped for having the external ID of a previous movie of the batch.
+	Duplicates int `json:"duplicates"`
+}
+
 // MovieService is a service for managing movies.
+// Create, Update and Import return an [InvalidError] if a movie has genres missing from the vocabulary, see [GenreService].
 type MovieService interface {
 	Get(ctx context.Context, id int64) (*Movie, error)
 	GetAll(cts context.Context, filter MovieFilter) ([]*Movie, error)
+	// Facets returns counts of the movies matching the filter for each of the [MovieFilter.Facets].
+	// Paging and sorting parameters of the filter are ignored.
+	Facets(ctx context.Context, filter MovieFilter) (MovieFacets, error)
+	// Export calls fn for each of the movies matching the filter, in the filter order, until fn returns an error.
+	// Paging parameters and facets of the filter are ignored, all the matching movies are exported.
+	Export(ctx context.Context, filter MovieFilter, fn func(m *Movie) error) error
+	// Similar returns the movies most similar to the [SimilarMovieFilter.MovieID] movie, most similar first.
+	// Similarity is based on the genres overlap, the release dates proximity and the runtimes similarity.
+	Similar(ctx context.Context, filter SimilarMovieFilter) ([]*Movie, error)
+	// Create creates the movie on behalf of the user of the context, see [NewContextWithUserID].
+	// Likely duplicates of the movie are detected in the mode carried by the context, see [NewContextWithDuplicateMode].
 	Create(ctx context.Context, m *Movie) error
 	Update(ctx context.Context, m *Movie) error
 	Delete(ctx context.Context, id int64) error
+	// Import creates the movies in bulk with their statuses, drafts if empty, external IDs and certifications.
+	// The movies are created by the user of the context, if any. The movies must be valid. Their IDs are not set.
+	Import(ctx context.Context, movies []*Movie) error
+	// Upsert creates or updates the movies in bulk by their [Movie.ExternalIDs] of the source. The movies must be valid.
+	// Only the title, release year, runtime and genres are updated, and movies that didn't change are left intact.
+	// Their IDs are not set.
+	Upsert(ctx context.Context, source string, movies []*Movie) (UpsertStats, error)
+	// GetByExternalID returns the movie having the ID in the external source.
+	GetByExternalID(ctx context.Context, source, id string) (*Movie, error)
+	// Translations returns the translations of the movie ordered by language.
+	Translations(ctx context.Context, id int64) ([]*MovieTranslation, error)
+	// SetTranslation creates or replaces the translation of the movie to the [MovieTranslation.Language].
+	SetTranslation(ctx context.Context, id int64, t *MovieTranslation) error
+	DeleteTranslation(ctx context.Context, id int64, lang string) error
+	// Stats returns the statistics of the catalogue as of the last refresh, see [MovieService.RefreshStats].
+	// The recent movies are restricted to the ones visible to the user, see [Movie.VisibleTo]. Nil user matches any movie.
+	Stats(ctx context.Context, visibleTo *User) (*MovieStats, error)
+	// RefreshStats recalculates the statistics of the catalogue.
+	RefreshStats(ctx context.Context) error
+	// Changes returns the changes of the movies made after the [MovieChangeFilter.Since] position, oldest first.
+	Changes(ctx context.Context, filter MovieChangeFilter) ([]*MovieChange, error)
+	// SequenceChanges appends the changes committed since the last call to the feed and returns their number.
+	// The changes aren't returned by [MovieService.Changes] until then, so it must be called periodically.
+	SequenceChanges(ctx context.Context) (int, error)
 }
@@ -0,0 +1,155 @@
+package greenlight
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+)
+
+// Person represents a person involved in making movies.
+type Person struct {
+	ID        int64
+	Name      string
+	BirthDate time.Time
+	Biography string
+	Version   int32
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (p *Person) Valid() error {
+	err := NewInvalidError("Person is invalid.")
+
+	if p.ID < 0 {
+		err.AddViolationMsg("ID", "Must be greater or equal to 0.")
+	}
+
+	if p.Name == "" {
+		err.AddViolationMsg("name", "Must be provided.")
+	}
+	if utf8.RuneCountInString(p.Name) > 500 {
+		err.AddViolationMsg("name", "Must not be more than 500 characters long.")
+	}
+
+	if !p.BirthDate.IsZero() && p.BirthDate.After(time.Now()) {
+		err.AddViolationMsg("birth_date", "Must not be in the future.")
+	}
+
+	if utf8.RuneCountInString(p.Biography) > 10_000 {
+		err.AddViolationMsg("biography", "Must not be more than 10000 characters long.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// PersonFilter is a filter used to retrieve people.
+type PersonFilter struct {
+	// Name is a part of the person name, matched case-insensitively.
+	Name string
+	// Page is the number of the page to return.
+	Page int
+	// PageSize is the size of the page.
+	PageSize int
+	// Sort is a name of the [Person] field to sort results on. To sort in descending order, prepend '-' to the field name.
+	Sort string
+}
+
+func (f *PersonFilter) Valid() error {
+	err := NewInvalidError("Person filter parameter(s) is/are invalid.")
+
+	if f.Page < 1 || f.Page > 10_000_000 {
+		err.AddViolationMsg("page", "Must be between 1 and 10_000_000.")
+	}
+
+	if f.PageSize < 1 || f.PageSize > 100 {
+		err.AddViolationMsg("page_size", "Must be between 1 and 100.")
+	}
+
+	switch f.Sort {
+	case "id", "name", "birth_date":
+	case "-id", "-name", "-birth_date":
+	default:
+		err.AddViolationMsg("sort", "Parameter is incorrect.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// CreditRole is a role of a person in making a movie.
+type CreditRole string
+
+const (
+	CreditActor    CreditRole = "actor"
+	CreditDirector CreditRole = "director"
+	CreditWriter   CreditRole = "writer"
+)
+
+// Credit represents a participation of a person in a movie.
+type Credit struct {
+	MovieID  int64
+	PersonID int64
+	Role     CreditRole
+	// Character is a name of the character played by an actor.
+	Character string
+	// Billing is the position of the credit in the movie credits, starting from 1.
+	Billing int
+
+	// Person is the credited person. Only populated when retrieving the movie credits.
+	Person *Person
+	// Movie is the credited movie. Only populated when retrieving the person filmography.
+	Movie *Movie
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (c *Credit) Valid() error {
+	err := NewInvalidError("Credit is invalid.")
+
+	if c.PersonID <= 0 {
+		err.AddViolationMsg("person_id", "Must be greater than 0.")
+	}
+
+	switch c.Role {
+	case CreditActor:
+	case CreditDirector, CreditWriter:
+		if c.Character != "" {
+			err.AddViolationMsg("character", "Must only be provided for actors.")
+		}
+	default:
+		err.AddViolationMsg("role", `Must be one of "actor", "director" or "writer".`)
+	}
+
+	if utf8.RuneCountInString(c.Character) > 500 {
+		err.AddViolationMsg("character", "Must not be more than 500 characters long.")
+	}
+
+	if c.Billing < 1 {
+		err.AddViolationMsg("billing", "Must be greater than 0.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// PersonService is a service for managing people and their movie credits.
+type PersonService interface {
+	Get(ctx context.Context, id int64) (*Person, error)
+	GetAll(ctx context.Context, filter PersonFilter) ([]*Person, error)
+	Create(ctx context.Context, p *Person) error
+	Update(ctx context.Context, p *Person) error
+	Delete(ctx context.Context, id int64) error
+
+	// MovieCredits returns credits of the movies ordered by the movie ID, role and billing, with the credited people.
+	MovieCredits(ctx context.Context, movieIDs ...int64) ([]*Credit, error)
+	// SetMovieCredits replaces all the credits of the movie.
+	SetMovieCredits(ctx context.Context, movieID int64, credits []*Credit) error
+	// Filmography returns credits of the person ordered by the movie release date, with the credited movies.
+	// The movies are restricted to the ones visible to the user, see [Movie.VisibleTo]. Nil user matches any movie.
+	Filmography(ctx context.Context, personID int64, visibleTo *User) ([]*Credit, error)
+}
@@ -0,0 +1,108 @@
+package greenlight
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+
+	// Register decoders of the supported poster formats.
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+// PosterOriginal is a name of the poster image as it was uploaded.
+const PosterOriginal = "original"
+
+// PosterThumbnails are widths of the poster thumbnails keyed by the thumbnail name.
+var PosterThumbnails = map[string]int{
+	"w185": 185,
+	"w342": 342,
+}
+
+// PosterImage is an encoded poster image.
+type PosterImage struct {
+	Data        []byte
+	ContentType string
+}
+
+// NewPosterImages validates the uploaded poster and returns it along with its thumbnails, keyed by the image name.
+// Thumbnails are JPEG encoded.
+func NewPosterImages(data []byte) (_ map[string]*PosterImage, err error) {
+	defer multierr.Wrap(&err, "greenlight.NewPosterImages")
+
+	ct := http.DetectContentType(data)
+	switch ct {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		e := NewInvalidError("Poster is invalid.")
+		e.AddViolationMsg("poster", "Must be a JPEG, PNG or GIF image.")
+		return nil, e
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		e := NewInvalidError("Poster is invalid.")
+		e.AddViolationMsg("poster", "Image is corrupted.")
+		return nil, e
+	}
+	if cfg.Width < 100 || cfg.Height < 100 || cfg.Width > 8000 || cfg.Height > 8000 {
+		e := NewInvalidError("Poster is invalid.")
+		e.AddViolationMsg("poster", "Image dimensions must be between 100x100 and 8000x8000 pixels.")
+		return nil, e
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		e := NewInvalidError("Poster is invalid.")
+		e.AddViolationMsg("poster", "Image is corrupted.")
+		return nil, e
+	}
+
+	imgs := map[string]*PosterImage{
+		PosterOriginal: {Data: data, ContentType: ct},
+	}
+	for name, width := range PosterThumbnails {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(img, width), &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		imgs[name] = &PosterImage{Data: buf.Bytes(), ContentType: "image/jpeg"}
+	}
+	return imgs, nil
+}
+
+// resize scales the image to the width preserving the aspect ratio.
+// Images narrower than the width are not upscaled.
+// Each destination pixel is an average of the source pixels it covers.
+func resize(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	if b.Dx() <= width {
+		width = b.Dx()
+	}
+	height := max(1, b.Dy()*width/b.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/width)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,89 @@
+package greenlight
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+)
+
+// Review represents a review of a movie by a user.
+type Review struct {
+	ID      int64
+	MovieID int64
+	UserID  int64
+	// Rating is a rating of the movie from 1 to 10.
+	Rating    int
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int32
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (r *Review) Valid() error {
+	err := NewInvalidError("Review is invalid.")
+
+	if r.ID < 0 {
+		err.AddViolationMsg("ID", "Must be greater or equal to 0.")
+	}
+
+	if r.Rating < 1 || r.Rating > 10 {
+		err.AddViolationMsg("rating", "Must be between 1 and 10.")
+	}
+
+	if utf8.RuneCountInString(r.Text) > 10_000 {
+		err.AddViolationMsg("text", "Must not be more than 10000 characters long.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// ReviewFilter is a filter used to retrieve reviews of a movie.
+type ReviewFilter struct {
+	// MovieID is the ID of the reviewed movie.
+	MovieID int64
+	// Page is the number of the page to return.
+	Page int
+	// PageSize is the size of the page.
+	PageSize int
+	// Sort is a name of the [Review] field to sort results on. To sort in descending order, prepend '-' to the field name.
+	Sort string
+}
+
+func (f *ReviewFilter) Valid() error {
+	err := NewInvalidError("Review filter parameter(s) is/are invalid.")
+
+	if f.Page < 1 || f.Page > 10_000_000 {
+		err.AddViolationMsg("page", "Must be between 1 and 10_000_000.")
+	}
+
+	if f.PageSize < 1 || f.PageSize > 100 {
+		err.AddViolationMsg("page_size", "Must be between 1 and 100.")
+	}
+
+	switch f.Sort {
+	case "id", "rating", "created_at", "updated_at":
+	case "-id", "-rating", "-created_at", "-updated_at":
+	default:
+		err.AddViolationMsg("sort", "Parameter is incorrect.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// ReviewService is a service for managing reviews.
+// Creating, updating and deleting a review updates the rating of the reviewed movie.
+type ReviewService interface {
+	Get(ctx context.Context, id int64) (*Review, error)
+	GetAll(ctx context.Context, filter ReviewFilter) ([]*Review, error)
+	// Create creates a review. A user can only review a movie once.
+	Create(ctx context.Context, r *Review) error
+	Update(ctx context.Context, r *Review) error
+	Delete(ctx context.Context, id int64) error
+}
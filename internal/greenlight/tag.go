@@ -0,0 +1,100 @@
+package greenlight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MovieTag is a free-form tag of a movie added by users, e.g. "time-travel". Unlike genres, tags aren't curated.
+// Each user adding the tag to the movie votes for it.
+type MovieTag struct {
+	Tag string `json:"tag"`
+	// Votes is the number of the users that added the tag to the movie.
+	Votes int `json:"votes"`
+	// Voted reports whether the user of the context added the tag to the movie, see [UserIDFromContext].
+	Voted bool `json:"voted"`
+}
+
+// TagCount is a tag with the number of the movies tagged with it and the total number of votes.
+type TagCount struct {
+	Tag    string `json:"tag"`
+	Movies int    `json:"movies"`
+	Votes  int    `json:"votes"`
+}
+
+// NormalizeTag returns the canonical form of the tag, e.g. "Based on Novel" becomes "based-on-novel".
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// TagValid returns an error if the tag isn't a valid normalized tag, otherwise nil.
+func TagValid(tag string) error {
+	err := NewInvalidError("Tag is invalid.")
+
+	if !slugRx.MatchString(tag) {
+		err.AddViolationMsg("tag", "Must consist of lowercase letters and digits separated by single dashes.")
+	}
+	if len(tag) > 50 {
+		err.AddViolationMsg("tag", "Must not be more than 50 bytes long.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// TagFilter is a filter used to retrieve popular tags.
+type TagFilter struct {
+	// Prefix is a prefix of the tags. Empty value matches any tag.
+	Prefix string
+	// Page is the number of the page to return.
+	Page int
+	// PageSize is the size of the page.
+	PageSize int
+}
+
+func (f *TagFilter) Valid() error {
+	err := NewInvalidError("Tag filter parameter(s) is/are invalid.")
+
+	if len(f.Prefix) > 50 {
+		err.AddViolationMsg("prefix", "Must not be more than 50 bytes long.")
+	}
+
+	if f.Page < 1 || f.Page > 10_000_000 {
+		err.AddViolationMsg("page", "Must be between 1 and 10_000_000.")
+	}
+
+	if f.PageSize < 1 || f.PageSize > 100 {
+		err.AddViolationMsg("page_size", "Must be between 1 and 100.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// TagService is a service for managing movie tags.
+// The user adding or removing tags is taken from the context, see [UserIDFromContext].
+type TagService interface {
+	// MovieTags returns the tags of the movie, most voted first.
+	MovieTags(ctx context.Context, movieID int64) ([]*MovieTag, error)
+	// AddTag adds the tag to the movie on behalf of the user, voting for the tag if other users already added it.
+	AddTag(ctx context.Context, movieID int64, tag string) error
+	// RemoveTag removes the vote of the user for the tag of the movie. If all is true, the tag is removed altogether.
+	RemoveTag(ctx context.Context, movieID int64, tag string, all bool) error
+	// Popular returns the tags ordered by the number of votes, most voted first.
+	Popular(ctx context.Context, filter TagFilter) ([]*TagCount, error)
+}
+
+// tagsValid returns an error if any of the tags isn't a valid normalized tag, otherwise nil.
+func tagsValid(tags []string) error {
+	for _, t := range tags {
+		if TagValid(t) != nil {
+			return fmt.Errorf("Invalid tag %q.", t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package greenlight
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// languageRx matches BCP 47 language tags in the lowercase form, e.g. "en" or "pt-br".
+var languageRx = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLanguage returns the language tag in the canonical form used by [MovieTranslation].
+func NormalizeLanguage(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// LanguageFallbacks returns the languages to try, in order, for the preferred languages.
+// Each language is followed by its less specific tags, e.g. "pt-br" is followed by "pt".
+func LanguageFallbacks(langs []string) []string {
+	var fallbacks []string
+	for _, l := range langs {
+		for tag := l; tag != ""; {
+			if !slices.Contains(fallbacks, tag) {
+				fallbacks = append(fallbacks, tag)
+			}
+			i := strings.LastIndexByte(tag, '-')
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return fallbacks
+}
+
+// MovieTranslation represents a translation of the [Movie] details to a language.
+type MovieTranslation struct {
+	// Language is a lowercase BCP 47 language tag, e.g. "de" or "pt-br". See [NormalizeLanguage].
+	Language string `json:"language"`
+	Title    string `json:"title"`
+	Synopsis string `json:"synopsis,omitempty"`
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (t *MovieTranslation) Valid() error {
+	err := NewInvalidError("Movie translation is invalid.")
+
+	if !languageRx.MatchString(t.Language) {
+		err.AddViolationMsg("language", "Must be a BCP 47 language tag.")
+	}
+
+	if t.Title == "" {
+		err.AddViolationMsg("title", "Must be provided.")
+	}
+	if utf8.RuneCountInString(t.Title) > 500 {
+		err.AddViolationMsg("title", "Must not be more than 500 characters long.")
+	}
+
+	if utf8.RuneCountInString(t.Synopsis) > 5000 {
+		err.AddViolationMsg("synopsis", "Must not be more than 5000 characters long.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
@@ -16,7 +16,28 @@ type User struct {
 	Name     string   `json:"name"`
 	Email    string   `json:"email"`
 	Password Password `json:"-"`
-	Version  int      `json:"-"`
+	Role     Role     `json:"-"`
+	// MaxCertification is the maximum certification of the movies shown to the user. Zero value means no limit.
+	MaxCertification Certification `json:"-"`
+	Version          int           `json:"-"`
+}
+
+// Role is a role of a user, granting permissions.
+type Role string
+
+const (
+	// RoleUser is a role of regular users.
+	RoleUser Role = "user"
+	// RoleEditor is a role of users maintaining the catalogue.
+	RoleEditor Role = "editor"
+	// RoleAdmin is a role of users with all the permissions.
+	RoleAdmin Role = "admin"
+)
+
+// Grants reports whether the role grants the permissions of the other role.
+func (r Role) Grants(other Role) bool {
+	rank := map[Role]int{RoleUser: 1, RoleEditor: 2, RoleAdmin: 3}
+	return rank[r] >= rank[other] && rank[other] > 0
 }
 
 // Valid returns an error if the validation fails, otherwise nil.
@@ -102,6 +123,7 @@ func (p *Password) Matches(plaintext string) (_ bool, err error) {
 // UserService is a service for managing users.
 type UserService interface {
 	Get(ctx context.Context, email string) (*User, error)
+	GetByID(ctx context.Context, id int64) (*User, error)
 	Create(ctx context.Context, u *User) error
 	Update(ctx context.Context, u *User) error
 }
@@ -0,0 +1,169 @@
+package greenlight
+
+import (
+	"context"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Webhook represents a subscription of a user to the changes of the movies, delivered to the URL, see [MovieChange].
+type Webhook struct {
+	ID     int64
+	UserID int64
+	URL    string
+	// Events are the operations of the delivered changes. Empty matches any operation.
+	Events []MovieChangeOp
+	// Secret is the key of the HMAC-SHA256 signatures of the deliveries.
+	Secret string
+	// Active reports whether the changes are delivered. Webhooks are disabled automatically after repeated failures.
+	Active bool
+	// Failures is the number of the consecutive failed deliveries.
+	Failures  int
+	CreatedAt time.Time
+	Version   int32
+}
+
+// Valid returns an error if the validation fails, otherwise nil.
+func (w *Webhook) Valid() error {
+	err := NewInvalidError("Webhook is invalid.")
+
+	if w.ID < 0 {
+		err.AddViolationMsg("ID", "Must be greater or equal to 0.")
+	}
+
+	if u, e := url.Parse(w.URL); e != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err.AddViolationMsg("url", "Must be an absolute HTTP or HTTPS URL.")
+	} else if !publicHost(u.Hostname()) {
+		err.AddViolationMsg("url", "Must not point to a loopback, private or link-local address.")
+	}
+	if len(w.URL) > 2000 {
+		err.AddViolationMsg("url", "Must not be more than 2000 bytes long.")
+	}
+
+	for i, op := range w.Events {
+		switch op {
+		case MovieCreated, MovieUpdated, MovieDeleted:
+		default:
+			err.AddViolationMsg("events", `Must be "created", "updated" or "deleted".`)
+		}
+		if slices.Contains(w.Events[:i], op) {
+			err.AddViolationMsg("events", "Must not contain duplicate values.")
+		}
+	}
+
+	if len(w.Secret) < 16 || len(w.Secret) > 200 {
+		err.AddViolationMsg("secret", "Must be between 16 and 200 bytes long.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// publicHost reports whether the host isn't a loopback, private or link-local one. Host names other than localhost
+// are resolved only when delivering, so they're checked by the deliverer as well.
+func publicHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return false
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return true
+	}
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
+
+// WebhookDeliveryStatus is a status of a [WebhookDelivery].
+type WebhookDeliveryStatus string
+
+const (
+	// DeliveryPending deliveries are awaiting the first attempt or a retry.
+	DeliveryPending WebhookDeliveryStatus = "pending"
+	// DeliverySucceeded deliveries were acknowledged with a 2xx status code.
+	DeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// DeliveryFailed deliveries failed all the attempts.
+	DeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery represents a delivery of a movie change to a [Webhook].
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int64
+	Change    *MovieChange
+	Status    WebhookDeliveryStatus
+	// Attempts is the number of the attempts made.
+	Attempts int
+	// NextAttemptAt is the time of the next attempt of a pending delivery.
+	NextAttemptAt time.Time
+	// StatusCode is the HTTP status code of the last attempt response, 0 if there was no response.
+	StatusCode int
+	// Error describes the failure of the last attempt.
+	Error         string
+	LastAttemptAt time.Time
+	CreatedAt     time.Time
+
+	// Webhook is the webhook the change is delivered to. Only populated when claiming the deliveries.
+	Webhook *Webhook
+}
+
+// WebhookDeliveryFilter is a filter used to retrieve the deliveries of a webhook.
+type WebhookDeliveryFilter struct {
+	WebhookID int64
+	// Status is the status of the deliveries. Empty value matches any status.
+	Status WebhookDeliveryStatus
+	// Page is the number of the page to return.
+	Page int
+	// PageSize is the size of the page.
+	PageSize int
+}
+
+func (f *WebhookDeliveryFilter) Valid() error {
+	err := NewInvalidError("Webhook delivery filter parameter(s) is/are invalid.")
+
+	switch f.Status {
+	case "", DeliveryPending, DeliverySucceeded, DeliveryFailed:
+	default:
+		err.AddViolationMsg("status", `Must be one of "pending", "succeeded" or "failed".`)
+	}
+
+	if f.Page < 1 || f.Page > 10_000_000 {
+		err.AddViolationMsg("page", "Must be between 1 and 10_000_000.")
+	}
+
+	if f.PageSize < 1 || f.PageSize > 100 {
+		err.AddViolationMsg("page_size", "Must be between 1 and 100.")
+	}
+
+	if len(err.violations) != 0 {
+		return err
+	}
+	return nil
+}
+
+// WebhookService is a service for managing webhooks and their deliveries.
+// A delivery is created for each of the active webhooks of the editors matching a movie change, when the change
+// is appended to the feed.
+type WebhookService interface {
+	Get(ctx context.Context, id int64) (*Webhook, error)
+	// GetAll returns the webhooks of the user.
+	GetAll(ctx context.Context, userID int64) ([]*Webhook, error)
+	Create(ctx context.Context, w *Webhook) error
+	Update(ctx context.Context, w *Webhook) error
+	Delete(ctx context.Context, id int64) error
+	// Disable deactivates the webhook, so that no changes are delivered to it.
+	Disable(ctx context.Context, id int64) error
+
+	// Deliveries returns the deliveries of the webhook matching the filter, most recent first.
+	Deliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]*WebhookDelivery, error)
+	// ClaimDeliveries returns up to limit pending deliveries of the active webhooks due for an attempt,
+	// with their webhooks. The deliveries aren't returned to other callers for the lease duration.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*WebhookDelivery, error)
+	// RecordAttempt records the result of the attempt of the delivery and returns the number of the consecutive
+	// failed deliveries of its webhook, see [Webhook.Failures]. A succeeded delivery resets the number.
+	RecordAttempt(ctx context.Context, d *WebhookDelivery) (failures int, err error)
+}
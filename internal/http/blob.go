@@ -0,0 +1,45 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerBlobHandlers() {
+	s.router.Handle("GET /v1/blobs/{key...}", s.handlerFunc(s.handleBlobGet))
+}
+
+// blobURLVerifier is implemented by blob stores that serve objects through the server using signed URLs.
+type blobURLVerifier interface {
+	VerifyURL(key string, query url.Values) error
+}
+
+// handleBlobGet handles requests to download a blob.
+func (s *Server) handleBlobGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleBlobGet")
+
+	key := r.PathValue("key")
+	if v, ok := s.opts.blobStore.(blobURLVerifier); ok {
+		if err := v.VerifyURL(key, r.URL.Query()); err != nil {
+			return err
+		}
+	}
+
+	b, err := s.opts.blobStore.Get(r.Context(), key)
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	w.Header().Set("Content-Type", b.ContentType)
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	// The status is already sent, so the error can only be returned for logging.
+	if _, err := io.Copy(w, b); err != nil {
+		s.LogError(w, r, "Sending blob", err)
+	}
+	return nil
+}
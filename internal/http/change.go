@@ -0,0 +1,188 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerChangeHandlers() {
+	s.router.Handle("GET /v1/movies/changes", s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(s.handleMovieChangesGet))))
+	if s.opts.movieEventService != nil {
+		s.router.Handle("GET /v1/movies/events", s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(s.handleMovieEventsGet))))
+	}
+}
+
+// handleMovieChangesGet handles requests to get the changes of the movies made after the "since" continuation token.
+// Clients poll the feed with the returned "next" token to synchronize with the catalogue incrementally.
+// The feed contains the changes of all the movies, including the unpublished ones, so it's only available to editors.
+func (s *Server) handleMovieChangesGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieChangesGet")
+
+	filter := greenlight.MovieChangeFilter{
+		Limit: 100,
+	}
+
+	vs := r.URL.Query()
+	if err := queryInt64(vs, "since", &filter.Since); err != nil {
+		return err
+	}
+	if err := queryInt(vs, "limit", &filter.Limit); err != nil {
+		return err
+	}
+
+	if err := filter.Valid(); err != nil {
+		return err
+	}
+
+	changes, err := s.movieService.Changes(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	// An empty page returns the same token, so that clients keep polling from the same position.
+	next := filter.Since
+	if len(changes) != 0 {
+		next = changes[len(changes)-1].Seq
+	}
+	resp := struct {
+		Changes []*greenlight.MovieChange `json:"changes"`
+		Next    string                    `json:"next"`
+		// More reports whether there are more changes after the next token, i.e. the page is full.
+		More bool `json:"more"`
+	}{
+		Changes: changes,
+		Next:    strconv.FormatInt(next, 10),
+		More:    len(changes) == filter.Limit,
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// keepAliveInterval is the interval of the comments sent to keep idle event streams open through proxies.
+const keepAliveInterval = 15 * time.Second
+
+// handleMovieEventsGet handles requests to stream the changes of the movies as server-sent events.
+// The event ID is the position in the change feed, so the stream is resumed after the "Last-Event-ID" header
+// from the feed. Without the header, only the changes made after the request are sent.
+func (s *Server) handleMovieEventsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieEventsGet")
+
+	since := int64(-1)
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		if since, err = strconv.ParseInt(raw, 10, 64); err != nil || since < 0 {
+			return greenlight.NewInvalidError(`Invalid "Last-Event-ID" header format: %s`, raw)
+		}
+	}
+
+	// The stream outlasts the server write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		return err
+	}
+
+	// Subscribing before reading the feed ensures no change is missed in between; the duplicates are skipped.
+	ctx := r.Context()
+	events := s.opts.movieEventService.Subscribe(ctx)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so the errors can only be logged. The client reconnects and resumes the stream.
+	es := &eventStream{w: w, rc: rc, last: since}
+	if err := es.retry(); err != nil {
+		s.LogError(w, r, "Streaming movie events", err)
+		return nil
+	}
+	if since >= 0 {
+		for {
+			filter := greenlight.MovieChangeFilter{Since: es.last, Limit: 1000}
+			changes, err := s.movieService.Changes(ctx, filter)
+			if err != nil {
+				s.LogError(w, r, "Streaming movie events", err)
+				return nil
+			}
+			for _, c := range changes {
+				if err := es.send(c); err != nil {
+					s.LogError(w, r, "Streaming movie events", err)
+					return nil
+				}
+			}
+			if len(changes) < filter.Limit {
+				break
+			}
+		}
+	}
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case <-s.done:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case c, ok := <-events:
+			// The subscription is dropped if the client falls behind.
+			if !ok {
+				return nil
+			}
+			err = es.send(c)
+		case <-ticker.C:
+			err = es.keepAlive()
+		}
+		if err != nil {
+			s.LogError(w, r, "Streaming movie events", err)
+			return nil
+		}
+	}
+}
+
+// eventStream writes the movie changes as server-sent events, skipping the changes already sent.
+type eventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+	// last is the position of the last change sent.
+	last int64
+}
+
+// retry sends the reconnection time of the client.
+func (es *eventStream) retry() error {
+	if _, err := fmt.Fprintf(es.w, "retry: %d\n\n", time.Second.Milliseconds()); err != nil {
+		return err
+	}
+	return es.rc.Flush()
+}
+
+func (es *eventStream) send(c *greenlight.MovieChange) error {
+	if c.Seq <= es.last {
+		return nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(es.w, "id: %d\nevent: %s\ndata: %s\n\n", c.Seq, c.Op, data); err != nil {
+		return err
+	}
+	es.last = c.Seq
+	return es.rc.Flush()
+}
+
+func (es *eventStream) keepAlive() error {
+	if _, err := fmt.Fprint(es.w, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+	return es.rc.Flush()
+}
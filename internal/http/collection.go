@@ -0,0 +1,289 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerCollectionHandlers() {
+	s.router.Handle("GET /v1/collections", s.handlerFunc(s.handleCollectionsGet))
+	s.router.Handle("GET /v1/collections/{slug}", s.authenticateOptional(s.handlerFunc(s.handleCollectionGet)))
+
+	editor := func(h func(http.ResponseWriter, *http.Request) error) http.Handler {
+		return s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(h)))
+	}
+	s.router.Handle("GET /v1/admin/collections", editor(s.handleAdminCollectionsGet))
+	s.router.Handle("POST /v1/admin/collections", editor(s.handleCollectionCreate))
+	s.router.Handle("GET /v1/admin/collections/{id}", editor(s.handleAdminCollectionGet))
+	s.router.Handle("PATCH /v1/admin/collections/{id}", editor(s.handleCollectionUpdate))
+	s.router.Handle("DELETE /v1/admin/collections/{id}", editor(s.handleCollectionDelete))
+}
+
+// handleCollectionsGet handles requests to get the published collections without their movies.
+func (s *Server) handleCollectionsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleCollectionsGet")
+
+	cs, err := s.opts.collectionService.GetAll(r.Context(), true)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*collectionResponse, len(cs))
+	for i, c := range cs {
+		resp[i] = newCollectionResponse(c, false)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleCollectionGet handles requests to get a specified published collection with its movies.
+func (s *Server) handleCollectionGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleCollectionGet")
+
+	c, err := s.opts.collectionService.GetBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		return err
+	}
+	if !c.Published(time.Now()) {
+		return greenlight.ErrNotFound
+	}
+
+	resp, err := s.newCollectionMoviesResponse(r.Context(), c, false)
+	if err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleAdminCollectionsGet handles requests to get all the collections, including unpublished ones.
+func (s *Server) handleAdminCollectionsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleAdminCollectionsGet")
+
+	cs, err := s.opts.collectionService.GetAll(r.Context(), false)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*collectionResponse, len(cs))
+	for i, c := range cs {
+		resp[i] = newCollectionResponse(c, true)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleAdminCollectionGet handles requests to get a specified collection with its movies, published or not.
+func (s *Server) handleAdminCollectionGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleAdminCollectionGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	c, err := s.opts.collectionService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.newCollectionMoviesResponse(r.Context(), c, true)
+	if err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleCollectionCreate handles requests to create a collection.
+func (s *Server) handleCollectionCreate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleCollectionCreate")
+
+	var req struct {
+		Slug         string    `json:"slug"`
+		Title        string    `json:"title"`
+		Description  string    `json:"description"`
+		MovieIDs     []int64   `json:"movie_ids"`
+		PublishFrom  time.Time `json:"publish_from"`
+		PublishUntil time.Time `json:"publish_until"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	c := &greenlight.Collection{
+		Slug:         req.Slug,
+		Title:        req.Title,
+		Description:  req.Description,
+		MovieIDs:     req.MovieIDs,
+		PublishFrom:  req.PublishFrom,
+		PublishUntil: req.PublishUntil,
+	}
+	if err := c.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.collectionService.Create(r.Context(), c); err != nil {
+		return err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/admin/collections/%d", c.ID))
+	if err := s.sendResponse(w, r, http.StatusCreated, newCollectionResponse(c, true), headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleCollectionUpdate handles requests to update a specified collection.
+func (s *Server) handleCollectionUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleCollectionUpdate")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	c, err := s.opts.collectionService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	// use pointers to allow partial updates
+	var req struct {
+		Slug         *string    `json:"slug"`
+		Title        *string    `json:"title"`
+		Description  *string    `json:"description"`
+		MovieIDs     []int64    `json:"movie_ids"`
+		PublishFrom  *time.Time `json:"publish_from"`
+		PublishUntil *time.Time `json:"publish_until"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.Slug != nil {
+		c.Slug = *req.Slug
+	}
+	if req.Title != nil {
+		c.Title = *req.Title
+	}
+	if req.Description != nil {
+		c.Description = *req.Description
+	}
+	if req.MovieIDs != nil {
+		c.MovieIDs = req.MovieIDs
+	}
+	if req.PublishFrom != nil {
+		c.PublishFrom = *req.PublishFrom
+	}
+	if req.PublishUntil != nil {
+		c.PublishUntil = *req.PublishUntil
+	}
+
+	if err := c.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.collectionService.Update(r.Context(), c); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newCollectionResponse(c, true), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleCollectionDelete handles requests to delete a specified collection.
+func (s *Server) handleCollectionDelete(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleCollectionDelete")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	if err := s.opts.collectionService.Delete(r.Context(), id); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// collectionResponse represents a collection sent to clients.
+type collectionResponse struct {
+	ID           int64            `json:"id,omitempty"`
+	Slug         string           `json:"slug"`
+	Title        string           `json:"title"`
+	Description  string           `json:"description,omitempty"`
+	MovieIDs     []int64          `json:"movie_ids,omitempty"`
+	PublishFrom  *time.Time       `json:"publish_from,omitempty"`
+	PublishUntil *time.Time       `json:"publish_until,omitempty"`
+	Movies       []*movieResponse `json:"movies,omitempty"`
+}
+
+// newCollectionResponse returns a response for the collection.
+// If admin is false, fields only relevant to the editors are omitted.
+func newCollectionResponse(c *greenlight.Collection, admin bool) *collectionResponse {
+	resp := &collectionResponse{
+		Slug:        c.Slug,
+		Title:       c.Title,
+		Description: c.Description,
+	}
+	if admin {
+		resp.ID = c.ID
+		resp.MovieIDs = c.MovieIDs
+		if !c.PublishFrom.IsZero() {
+			resp.PublishFrom = &c.PublishFrom
+		}
+		if !c.PublishUntil.IsZero() {
+			resp.PublishUntil = &c.PublishUntil
+		}
+	}
+	return resp
+}
+
+// newCollectionMoviesResponse returns a response for the collection with its movies.
+// Unless admin is true, only the movies visible to the authenticated user are included.
+func (s *Server) newCollectionMoviesResponse(ctx context.Context, c *greenlight.Collection, admin bool) (*collectionResponse, error) {
+	var viewer *greenlight.User
+	if !admin {
+		var err error
+		if viewer, err = s.viewer(ctx); err != nil {
+			return nil, err
+		}
+	}
+	movies, err := s.opts.collectionService.Movies(ctx, c.ID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := newCollectionResponse(c, admin)
+	resp.Movies = make([]*movieResponse, len(movies))
+	for i, m := range movies {
+		if resp.Movies[i], err = s.newMovieResponse(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
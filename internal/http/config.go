@@ -1,6 +1,10 @@
 package http
 
-import "time"
+import (
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
 
 // Option represents a configuration option for an HTTP.
 type Option func(o *options)
@@ -12,8 +16,22 @@ type options struct {
 	writeTimeout    time.Duration
 	shutdownTimeout time.Duration
 	maxRequestBody  int64
+	maxPosterSize   int64
+	maxImportSize   int64
+	duplicateMode   greenlight.DuplicateMode
 	limiterRps      float64
 	limiterBurst    int
+
+	// Services of the features beyond movies, users and authentication.
+	blobStore         greenlight.BlobStore
+	personService     greenlight.PersonService
+	reviewService     greenlight.ReviewService
+	listService       greenlight.ListService
+	collectionService greenlight.CollectionService
+	genreService      greenlight.GenreService
+	tagService        greenlight.TagService
+	movieEventService greenlight.MovieEventService
+	webhookService    greenlight.WebhookService
 }
 
 // WithIdleTimeout sets the idle timeout.
@@ -51,6 +69,27 @@ func WithMaxRequestBody(sz int64) Option {
 	}
 }
 
+// WithMaxPosterSize sets the maximum size of the uploaded poster in bytes.
+func WithMaxPosterSize(sz int64) Option {
+	return func(o *options) {
+		o.maxPosterSize = sz
+	}
+}
+
+// WithMaxImportSize sets the maximum size of the imported movies data in bytes.
+func WithMaxImportSize(sz int64) Option {
+	return func(o *options) {
+		o.maxImportSize = sz
+	}
+}
+
+// WithDuplicateMode sets the mode of the duplicate detection for created movies.
+func WithDuplicateMode(mode greenlight.DuplicateMode) Option {
+	return func(o *options) {
+		o.duplicateMode = mode
+	}
+}
+
 // WithLimiterRps sets the HTTP rate limiter maximum requests per second.
 func WithLimiterRps(rps float64) Option {
 	return func(o *options) {
@@ -64,3 +103,66 @@ func WithLimiterBurst(burst int) Option {
 		o.limiterBurst = burst
 	}
 }
+
+// WithBlobStore sets the blob store used to store posters.
+func WithBlobStore(blobStore greenlight.BlobStore) Option {
+	return func(o *options) {
+		o.blobStore = blobStore
+	}
+}
+
+// WithPersonService sets the person service.
+func WithPersonService(personService greenlight.PersonService) Option {
+	return func(o *options) {
+		o.personService = personService
+	}
+}
+
+// WithReviewService sets the review service.
+func WithReviewService(reviewService greenlight.ReviewService) Option {
+	return func(o *options) {
+		o.reviewService = reviewService
+	}
+}
+
+// WithListService sets the user list service.
+func WithListService(listService greenlight.ListService) Option {
+	return func(o *options) {
+		o.listService = listService
+	}
+}
+
+// WithCollectionService sets the collection service.
+func WithCollectionService(collectionService greenlight.CollectionService) Option {
+	return func(o *options) {
+		o.collectionService = collectionService
+	}
+}
+
+// WithGenreService sets the genre service.
+func WithGenreService(genreService greenlight.GenreService) Option {
+	return func(o *options) {
+		o.genreService = genreService
+	}
+}
+
+// WithTagService sets the tag service.
+func WithTagService(tagService greenlight.TagService) Option {
+	return func(o *options) {
+		o.tagService = tagService
+	}
+}
+
+// WithMovieEventService sets the service streaming the movie changes.
+func WithMovieEventService(movieEventService greenlight.MovieEventService) Option {
+	return func(o *options) {
+		o.movieEventService = movieEventService
+	}
+}
+
+// WithWebhookService sets the webhook service.
+func WithWebhookService(webhookService greenlight.WebhookService) Option {
+	return func(o *options) {
+		o.webhookService = webhookService
+	}
+}
@@ -42,6 +42,8 @@ func ErrorStatusCode(err error) int {
 		return http.StatusTooManyRequests
 	case errors.As(err, new(*greenlight.UnauthorizedError)):
 		return http.StatusUnauthorized
+	case errors.As(err, new(*greenlight.ForbiddenError)):
+		return http.StatusForbidden
 	case errors.As(err, new(*greenlight.InternalError)):
 		fallthrough
 	default:
@@ -56,6 +58,7 @@ func ErrorBody(err error) any {
 	var cftErr *greenlight.ConflictError
 	var rateErr *greenlight.RateLimitError
 	var unErr *greenlight.UnauthorizedError
+	var forbErr *greenlight.ForbiddenError
 
 	switch {
 	case errors.As(err, &nfErr):
@@ -68,11 +71,16 @@ func ErrorBody(err error) any {
 		}
 		return ValidationErrorResponse{Msg: invErr.Msg, Fields: m}
 	case errors.As(err, &cftErr):
+		if len(cftErr.IDs) != 0 {
+			return ConflictErrorResponse{Msg: cftErr.Msg, IDs: cftErr.IDs}
+		}
 		return ErrorResponse{Msg: cftErr.Msg}
 	case errors.As(err, &rateErr):
 		return ErrorResponse{Msg: rateErr.Msg}
 	case errors.As(err, &unErr):
 		return ErrorResponse{Msg: unErr.Msg}
+	case errors.As(err, &forbErr):
+		return ErrorResponse{Msg: forbErr.Msg}
 	case errors.As(err, &intErr):
 		fallthrough
 	default:
@@ -86,6 +94,11 @@ type ErrorResponse struct {
 	Msg string `json:"message,omitempty"`
 }
 
+type ConflictErrorResponse struct {
+	Msg string  `json:"message,omitempty"`
+	IDs []int64 `json:"conflicting_ids"`
+}
+
 type ValidationErrorResponse struct {
 	Msg    string            `json:"message,omitempty"`
 	Fields map[string]string `json:"invalid_fields,omitempty"`
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerGenreHandlers() {
+	s.router.Handle("GET /v1/genres", s.handlerFunc(s.handleGenresGet))
+
+	editor := func(h func(http.ResponseWriter, *http.Request) error) http.Handler {
+		return s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(h)))
+	}
+	s.router.Handle("POST /v1/admin/genres", editor(s.handleGenreCreate))
+	s.router.Handle("PATCH /v1/admin/genres/{slug}", editor(s.handleGenreRename))
+	s.router.Handle("POST /v1/admin/genres/{slug}/merge", editor(s.handleGenreMerge))
+}
+
+// handleGenresGet handles requests to get all the genres.
+func (s *Server) handleGenresGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleGenresGet")
+
+	genres, err := s.opts.genreService.GetAll(r.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, genres, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleGenreCreate handles requests to create a genre.
+func (s *Server) handleGenreCreate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleGenreCreate")
+
+	var req struct {
+		Slug string `json:"slug"`
+		Name string `json:"name"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	g := &greenlight.Genre{
+		Slug: req.Slug,
+		Name: req.Name,
+	}
+	if err := g.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.genreService.Create(r.Context(), g); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusCreated, g, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleGenreRename handles requests to change the slug or the name of a specified genre.
+func (s *Server) handleGenreRename(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleGenreRename")
+
+	slug := r.PathValue("slug")
+	g, err := s.opts.genreService.Get(r.Context(), slug)
+	if err != nil {
+		return err
+	}
+
+	// use pointers to allow partial updates
+	var req struct {
+		Slug *string `json:"slug"`
+		Name *string `json:"name"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.Slug != nil {
+		g.Slug = *req.Slug
+	}
+	if req.Name != nil {
+		g.Name = *req.Name
+	}
+
+	if err := g.Valid(); err != nil {
+		return err
+	}
+	// Renaming rewrites the movies of the genre, so it may outlast the server write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		return err
+	}
+	if err := s.opts.genreService.Rename(r.Context(), slug, g); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, g, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleGenreMerge handles requests to merge a specified genre into another one.
+func (s *Server) handleGenreMerge(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleGenreMerge")
+
+	var req struct {
+		Into string `json:"into"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+	if req.Into == "" {
+		e := greenlight.NewInvalidError("Merge parameter(s) is/are invalid.")
+		e.AddViolationMsg("into", "Must be provided.")
+		return e
+	}
+
+	// Merging rewrites the movies of the genre, so it may outlast the server write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		return err
+	}
+	if err := s.opts.genreService.Merge(r.Context(), r.PathValue("slug"), req.Into); err != nil {
+		return err
+	}
+
+	g, err := s.opts.genreService.Get(r.Context(), req.Into)
+	if err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, g, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+// requestLanguages returns the languages preferred by the client, most preferred first.
+// The "lang" query parameter takes precedence over the "Accept-Language" header.
+func requestLanguages(r *http.Request) []string {
+	if vs := r.URL.Query(); vs.Has("lang") {
+		var langs []string
+		for _, l := range strings.Split(vs.Get("lang"), ",") {
+			if l = greenlight.NormalizeLanguage(l); l != "" {
+				langs = append(langs, l)
+			}
+		}
+		return langs
+	}
+	return parseAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// parseAcceptLanguage returns the languages of the "Accept-Language" header value ordered by their weights.
+// Malformed entries, the "*" wildcard and languages with zero weight are ignored.
+func parseAcceptLanguage(v string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var ws []weighted
+	for _, part := range strings.Split(v, ",") {
+		lang, params, _ := strings.Cut(part, ";")
+		lang = greenlight.NormalizeLanguage(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+
+		q := 1.0
+		if p, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			v, err := strconv.ParseFloat(p, 64)
+			if err != nil {
+				continue
+			}
+			q = v
+		}
+		if q <= 0 {
+			continue
+		}
+		ws = append(ws, weighted{lang, q})
+	}
+	// Stable sort keeps the header order of languages having the same weight.
+	slices.SortStableFunc(ws, func(a, b weighted) int { return cmp.Compare(b.q, a.q) })
+
+	langs := make([]string, len(ws))
+	for i, w := range ws {
+		langs[i] = w.lang
+	}
+	return langs
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"de", []string{"de"}},
+		{"pt-BR, pt;q=0.8, en;q=0.5", []string{"pt-br", "pt", "en"}},
+		{"en;q=0.5, fr", []string{"fr", "en"}},
+		{"fr;q=0.7, de;q=0.7, *;q=0.1", []string{"fr", "de"}},
+		{"es;q=0, it;q=bad, nl", []string{"nl"}},
+	}
+	for _, tt := range tests {
+		if got := parseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("parseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
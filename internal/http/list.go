@@ -0,0 +1,432 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerListHandlers() {
+	s.router.Handle("GET /v1/users/me/lists", s.authenticate(s.handlerFunc(s.handleMyListsGet)))
+	s.router.Handle("POST /v1/users/me/lists", s.authenticate(s.handlerFunc(s.handleListCreate)))
+	s.router.Handle("GET /v1/users/me/lists/{id}", s.authenticate(s.handlerFunc(s.handleMyListGet)))
+	s.router.Handle("PATCH /v1/users/me/lists/{id}", s.authenticate(s.handlerFunc(s.handleListUpdate)))
+	s.router.Handle("DELETE /v1/users/me/lists/{id}", s.authenticate(s.handlerFunc(s.handleListDelete)))
+	s.router.Handle("POST /v1/users/me/lists/{id}/entries", s.authenticate(s.handlerFunc(s.handleListEntryCreate)))
+	s.router.Handle("PATCH /v1/users/me/lists/{id}/entries/{movie_id}", s.authenticate(s.handlerFunc(s.handleListEntryUpdate)))
+	s.router.Handle("DELETE /v1/users/me/lists/{id}/entries/{movie_id}", s.authenticate(s.handlerFunc(s.handleListEntryDelete)))
+	s.router.Handle("GET /v1/users/{id}/lists", s.handlerFunc(s.handleUserListsGet))
+	s.router.Handle("GET /v1/lists/{id}", s.authenticateOptional(s.handlerFunc(s.handleListGet)))
+}
+
+// handleMyListsGet handles requests to get all the lists of the authenticated user.
+func (s *Server) handleMyListsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMyListsGet")
+
+	userID := greenlight.UserIDFromContext(r.Context())
+	// Make sure the watchlist exists, so it is always returned.
+	if _, err := s.opts.listService.Watchlist(r.Context(), userID); err != nil {
+		return err
+	}
+	lists, err := s.opts.listService.GetAll(r.Context(), userID, false)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*listResponse, len(lists))
+	for i, l := range lists {
+		resp[i] = newListResponse(l)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleUserListsGet handles requests to get the public lists of a specified user.
+func (s *Server) handleUserListsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleUserListsGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	lists, err := s.opts.listService.GetAll(r.Context(), id, true)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*listResponse, len(lists))
+	for i, l := range lists {
+		resp[i] = newListResponse(l)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleListGet handles requests to get a specified public or unlisted list with its entries.
+func (s *Server) handleListGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleListGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	l, err := s.opts.listService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	// Private lists are reported as not existing, so their IDs aren't disclosed.
+	if l.Visibility == greenlight.ListPrivate {
+		return greenlight.ErrNotFound
+	}
+
+	resp, err := s.newListEntriesResponse(r.Context(), l)
+	if err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMyListGet handles requests to get a specified list of the authenticated user with its entries.
+func (s *Server) handleMyListGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMyListGet")
+
+	l, err := s.myList(r)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.newListEntriesResponse(r.Context(), l)
+	if err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleListCreate handles requests to create a list of the authenticated user.
+func (s *Server) handleListCreate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleListCreate")
+
+	var req struct {
+		Name       string `json:"name"`
+		Visibility string `json:"visibility"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	l := &greenlight.List{
+		UserID:     greenlight.UserIDFromContext(r.Context()),
+		Name:       req.Name,
+		Visibility: greenlight.ListVisibility(req.Visibility),
+	}
+	if l.Visibility == "" {
+		l.Visibility = greenlight.ListPrivate
+	}
+	if err := l.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.listService.Create(r.Context(), l); err != nil {
+		return err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/users/me/lists/%d", l.ID))
+	if err := s.sendResponse(w, r, http.StatusCreated, newListResponse(l), headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleListUpdate handles requests to update a specified list of the authenticated user.
+func (s *Server) handleListUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleListUpdate")
+
+	l, err := s.myList(r)
+	if err != nil {
+		return err
+	}
+
+	// use pointers to allow partial updates
+	var req struct {
+		Name       *string `json:"name"`
+		Visibility *string `json:"visibility"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.Name != nil {
+		if l.Watchlist && *req.Name != l.Name {
+			return greenlight.NewConflictError("The watchlist can't be renamed.")
+		}
+		l.Name = *req.Name
+	}
+	if req.Visibility != nil {
+		l.Visibility = greenlight.ListVisibility(*req.Visibility)
+	}
+
+	if err := l.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.listService.Update(r.Context(), l); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newListResponse(l), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleListDelete handles requests to delete a specified list of the authenticated user.
+func (s *Server) handleListDelete(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleListDelete")
+
+	l, err := s.myList(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.opts.listService.Delete(r.Context(), l.ID); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleListEntryCreate handles requests to add a movie to a specified list of the authenticated user.
+func (s *Server) handleListEntryCreate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleListEntryCreate")
+
+	l, err := s.myList(r)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		MovieID  int64  `json:"movie_id"`
+		Position int    `json:"position"`
+		Notes    string `json:"notes"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	e := &greenlight.ListEntry{
+		ListID:   l.ID,
+		MovieID:  req.MovieID,
+		Position: req.Position,
+		Notes:    req.Notes,
+	}
+	if err := e.Valid(); err != nil {
+		return err
+	}
+	// Only the visible movies are listed, so that the lists don't disclose the other ones.
+	if _, err := s.visibleMovie(r.Context(), e.MovieID); err != nil {
+		if errors.Is(err, greenlight.ErrNotFound) {
+			return greenlight.NewInvalidError("Movie %d doesn't exist.", e.MovieID)
+		}
+		return err
+	}
+	if err := s.opts.listService.AddEntry(r.Context(), e); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusCreated, newListEntryResponse(e, nil), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleListEntryUpdate handles requests to move or annotate a movie in a specified list of the authenticated user.
+func (s *Server) handleListEntryUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleListEntryUpdate")
+
+	l, err := s.myList(r)
+	if err != nil {
+		return err
+	}
+	movieIDRaw := r.PathValue("movie_id")
+	movieID, err := strconv.ParseInt(movieIDRaw, 10, 64)
+	if err != nil || movieID < 0 {
+		return greenlight.NewInvalidError("Invalid movie ID format: %s", movieIDRaw)
+	}
+
+	// The owner edits any of the entries, including the ones of the movies not visible anymore.
+	entries, err := s.opts.listService.Entries(r.Context(), l.ID, nil)
+	if err != nil {
+		return err
+	}
+	var e *greenlight.ListEntry
+	for _, v := range entries {
+		if v.MovieID == movieID {
+			e = v
+		}
+	}
+	if e == nil {
+		return greenlight.ErrNotFound
+	}
+
+	// use pointers to allow partial updates
+	var req struct {
+		Position *int    `json:"position"`
+		Notes    *string `json:"notes"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.Position != nil {
+		e.Position = *req.Position
+	}
+	if req.Notes != nil {
+		e.Notes = *req.Notes
+	}
+
+	if err := e.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.listService.UpdateEntry(r.Context(), e); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newListEntryResponse(e, nil), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleListEntryDelete handles requests to remove a movie from a specified list of the authenticated user.
+func (s *Server) handleListEntryDelete(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleListEntryDelete")
+
+	l, err := s.myList(r)
+	if err != nil {
+		return err
+	}
+	movieIDRaw := r.PathValue("movie_id")
+	movieID, err := strconv.ParseInt(movieIDRaw, 10, 64)
+	if err != nil || movieID < 0 {
+		return greenlight.NewInvalidError("Invalid movie ID format: %s", movieIDRaw)
+	}
+
+	if err := s.opts.listService.RemoveEntry(r.Context(), l.ID, movieID); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// myList returns the list of the authenticated user specified by the "id" path parameter.
+// The "watchlist" ID refers to the user watchlist. Lists of other users are reported as not existing.
+func (s *Server) myList(r *http.Request) (*greenlight.List, error) {
+	userID := greenlight.UserIDFromContext(r.Context())
+
+	idRaw := r.PathValue("id")
+	if idRaw == "watchlist" {
+		return s.opts.listService.Watchlist(r.Context(), userID)
+	}
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return nil, greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	l, err := s.opts.listService.Get(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if l.UserID != userID {
+		return nil, greenlight.ErrNotFound
+	}
+	return l, nil
+}
+
+// listResponse represents a list sent to clients.
+type listResponse struct {
+	ID         int64                `json:"id"`
+	UserID     int64                `json:"user_id"`
+	Name       string               `json:"name"`
+	Watchlist  bool                 `json:"watchlist"`
+	Visibility string               `json:"visibility"`
+	CreatedAt  time.Time            `json:"created_at"`
+	Entries    []*listEntryResponse `json:"entries,omitempty"`
+}
+
+func newListResponse(l *greenlight.List) *listResponse {
+	return &listResponse{
+		ID:         l.ID,
+		UserID:     l.UserID,
+		Name:       l.Name,
+		Watchlist:  l.Watchlist,
+		Visibility: string(l.Visibility),
+		CreatedAt:  l.CreatedAt,
+	}
+}
+
+// newListEntriesResponse returns a response for the list with the entries of the movies visible to the authenticated user.
+func (s *Server) newListEntriesResponse(ctx context.Context, l *greenlight.List) (*listResponse, error) {
+	viewer, err := s.viewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.opts.listService.Entries(ctx, l.ID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := newListResponse(l)
+	resp.Entries = make([]*listEntryResponse, len(entries))
+	for i, e := range entries {
+		mr, err := s.newMovieResponse(ctx, e.Movie)
+		if err != nil {
+			return nil, err
+		}
+		resp.Entries[i] = newListEntryResponse(e, mr)
+	}
+	return resp, nil
+}
+
+// listEntryResponse represents a list entry sent to clients.
+type listEntryResponse struct {
+	MovieID  int64          `json:"movie_id"`
+	Position int            `json:"position"`
+	Notes    string         `json:"notes,omitempty"`
+	AddedAt  time.Time      `json:"added_at"`
+	Movie    *movieResponse `json:"movie,omitempty"`
+}
+
+func newListEntryResponse(e *greenlight.ListEntry, movie *movieResponse) *listEntryResponse {
+	return &listEntryResponse{
+		MovieID:  e.MovieID,
+		Position: e.Position,
+		Notes:    e.Notes,
+		AddedAt:  e.AddedAt,
+		Movie:    movie,
+	}
+}
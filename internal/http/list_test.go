@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+// listService is an in-memory [greenlight.ListService] enforcing the unique list names of a user, like the database.
+type listService struct {
+	greenlight.ListService
+
+	mu    sync.Mutex
+	lists []*greenlight.List
+}
+
+func (s *listService) insert(l *greenlight.List) error {
+	for _, o := range s.lists {
+		if o.UserID == l.UserID && o.Name == l.Name {
+			return errors.New(`duplicate key value violates unique constraint "lists_user_id_name_key"`)
+		}
+	}
+	l.ID = int64(len(s.lists) + 1)
+	c := *l
+	s.lists = append(s.lists, &c)
+	return nil
+}
+
+func (s *listService) Get(_ context.Context, id int64) (*greenlight.List, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range s.lists {
+		if l.ID == id {
+			c := *l
+			return &c, nil
+		}
+	}
+	return nil, greenlight.ErrNotFound
+}
+
+func (s *listService) GetAll(_ context.Context, userID int64, _ bool) ([]*greenlight.List, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lists []*greenlight.List
+	for _, l := range s.lists {
+		if l.UserID == userID {
+			c := *l
+			lists = append(lists, &c)
+		}
+	}
+	return lists, nil
+}
+
+func (s *listService) Watchlist(_ context.Context, userID int64) (*greenlight.List, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range s.lists {
+		if l.UserID == userID && l.Watchlist {
+			c := *l
+			return &c, nil
+		}
+	}
+	l := &greenlight.List{UserID: userID, Name: greenlight.WatchlistName, Watchlist: true, Visibility: greenlight.ListPrivate}
+	if err := s.insert(l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *listService) Create(_ context.Context, l *greenlight.List) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insert(l)
+}
+
+func (s *listService) Update(_ context.Context, l *greenlight.List) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, o := range s.lists {
+		if o.ID == l.ID {
+			c := *l
+			s.lists[i] = &c
+			return nil
+		}
+	}
+	return greenlight.ErrNotFound
+}
+
+// TestListWatchlistNameReserved checks that a list named like the watchlist can't be created before the watchlist is,
+// which would make creating the watchlist fail.
+func TestListWatchlistNameReserved(t *testing.T) {
+	authService := greenlight.NewAuthService("secret")
+	s := NewServer(":0", nil, nil, authService, WithListService(&listService{}), WithMaxRequestBody(1<<20))
+	token, err := authService.CreateToken(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := do(http.MethodPost, "/v1/users/me/lists", `{"name": "Watchlist"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("creating a list named like the watchlist: status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	w := do(http.MethodPost, "/v1/users/me/lists", `{"name": "Favorites"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("creating a list: status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	id := strings.TrimPrefix(w.Header().Get("Location"), "/v1/users/me/lists/")
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		t.Fatalf("Location = %q, want the URL of the list", w.Header().Get("Location"))
+	}
+	if w := do(http.MethodPatch, "/v1/users/me/lists/"+id, `{"name": "Watchlist"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("renaming a list like the watchlist: status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	if w := do(http.MethodGet, "/v1/users/me/lists", ""); w.Code != http.StatusOK {
+		t.Errorf("getting the lists: status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := do(http.MethodPatch, "/v1/users/me/lists/watchlist", `{"name": "Watchlist", "visibility": "public"}`); w.Code != http.StatusOK {
+		t.Errorf("updating the watchlist: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"expvar"
 	"fmt"
 	"net"
@@ -172,6 +173,42 @@ func (s *Server) authenticate(h http.Handler) http.Handler {
 	})
 }
 
+// authenticateOptional returns a request handler that authenticates the user if the request has the "Authorization" header.
+// Requests without the header are handled anonymously.
+func (s *Server) authenticateOptional(h http.Handler) http.Handler {
+	auth := s.authenticate(h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Add("Vary", "Authorization")
+			h.ServeHTTP(w, r)
+			return
+		}
+		auth.ServeHTTP(w, r)
+	})
+}
+
+// requireRole returns a request handler that only allows authenticated users having the role.
+// It must be wrapped by [Server.authenticate].
+func (s *Server) requireRole(role greenlight.Role, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := "http.Server.requireRole"
+
+		u, err := s.userService.GetByID(r.Context(), greenlight.UserIDFromContext(r.Context()))
+		if err != nil {
+			if errors.Is(err, greenlight.ErrNotFound) {
+				err = greenlight.NewUnauthorizedError("Invalid or missing authentication token.")
+			}
+			s.Error(w, r, fmt.Errorf("%s: %w", op, err))
+			return
+		}
+		if !u.Role.Grants(role) {
+			s.Error(w, r, fmt.Errorf("%s: %w", op, greenlight.NewForbiddenError("You don't have permission to access this resource.")))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 func (s *Server) metrics(next http.Handler) http.Handler {
 	var (
 		totalRequestsReceived           = expvar.NewInt("total_requests_received")
@@ -2,22 +2,43 @@ package http
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/jsonpatch"
+	"github.com/denpeshkov/greenlight/internal/movieio"
 	"github.com/denpeshkov/greenlight/internal/multierr"
 )
 
 func (s *Server) registerMovieHandlers() {
-	s.router.Handle("GET /v1/movies/{id}", s.handlerFunc(s.handleMovieGet))
-	s.router.Handle("GET /v1/movies", s.handlerFunc(s.handleMoviesGet))
+	s.router.Handle("GET /v1/movies/{id}", s.authenticateOptional(s.handlerFunc(s.handleMovieGet)))
+	s.router.Handle("GET /v1/movies", s.authenticateOptional(s.handlerFunc(s.handleMoviesGet)))
 	s.router.Handle("POST /v1/movies", s.authenticate(s.handlerFunc(s.handleMovieCreate)))
-	s.router.Handle("PATCH /v1/movies/{id}", s.handlerFunc(s.handleMovieUpdate))
+	s.router.Handle("GET /v1/movies/lookup", s.authenticateOptional(s.handlerFunc(s.handleMovieLookup)))
+	s.router.Handle("GET /v1/movies/export", s.authenticateOptional(s.handlerFunc(s.handleMoviesExport)))
+	s.router.Handle("PATCH /v1/movies/{id}", s.authenticate(s.handlerFunc(s.handleMovieUpdate)))
 	s.router.Handle("DELETE /v1/movies/{id}", s.authenticate(s.handlerFunc(s.handleMovieDelete)))
+	s.router.Handle("PUT /v1/movies/{id}/status", s.authenticate(s.handlerFunc(s.handleMovieStatusUpdate)))
+	s.router.Handle("GET /v1/movies/{id}/similar", s.authenticateOptional(s.handlerFunc(s.handleMoviesSimilarGet)))
+	s.router.Handle("GET /v1/movies/{id}/translations", s.authenticateOptional(s.handlerFunc(s.handleMovieTranslationsGet)))
+	s.router.Handle("PUT /v1/movies/{id}/translations/{lang}", s.authenticate(s.handlerFunc(s.handleMovieTranslationPut)))
+	s.router.Handle("DELETE /v1/movies/{id}/translations/{lang}", s.authenticate(s.handlerFunc(s.handleMovieTranslationDelete)))
+
+	// Imported movies are validated against the genre vocabulary.
+	if s.opts.genreService != nil {
+		s.router.Handle("POST /v1/movies/import", s.authenticate(s.handlerFunc(s.handleMoviesImport)))
+	}
 }
 
 // handleMovieGet handles requests to get a specified movie.
@@ -30,26 +51,43 @@ func (s *Server) handleMovieGet(w http.ResponseWriter, r *http.Request) (err err
 		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
 	}
 
-	m, err := s.movieService.Get(r.Context(), id)
+	vs := r.URL.Query()
+	var fields []string
+	queryList(vs, "fields", &fields)
+	if err := greenlight.MovieFieldsValid(fields); err != nil {
+		e := greenlight.NewInvalidError("Movie parameter(s) is/are invalid.")
+		e.AddViolation("fields", err)
+		return e
+	}
+	include, err := s.movieInclude(vs)
 	if err != nil {
 		return err
 	}
 
-	resp := struct {
-		ID          int64    `json:"id"`
-		Title       string   `json:"title"`
-		ReleaseDate date     `json:"release_date,omitempty"`
-		Runtime     int      `json:"runtime,omitempty"`
-		Genres      []string `json:"genres,omitempty"`
-	}{
-		ID:          m.ID,
-		Title:       m.Title,
-		ReleaseDate: date(m.ReleaseDate),
-		Runtime:     m.Runtime,
-		Genres:      m.Genres,
+	viewer, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+	ctx := greenlight.NewContextWithLanguages(r.Context(), requestLanguages(r))
+	m, err := s.movieService.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !m.VisibleTo(viewer, time.Now()) {
+		return greenlight.ErrNotFound
 	}
 
-	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+	resp, err := s.movieResponses(r.Context(), []*greenlight.Movie{m}, fields, include)
+	if err != nil {
+		return err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Vary", "Accept-Language")
+	if m.Language != "" {
+		headers.Set("Content-Language", m.Language)
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, resp[0], headers); err != nil {
 		return err
 	}
 	return nil
@@ -59,112 +97,283 @@ func (s *Server) handleMovieGet(w http.ResponseWriter, r *http.Request) (err err
 func (s *Server) handleMoviesGet(w http.ResponseWriter, r *http.Request) (err error) {
 	defer multierr.Wrap(&err, "http.Server.handleMoviesGet")
 
-	filter := greenlight.MovieFilter{
-		Title:    "",
-		Genres:   []string{},
-		Page:     1,
-		PageSize: 20,
-		Sort:     "id",
+	vs := r.URL.Query()
+	filter, err := queryMovieFilter(vs, greenlight.UserIDFromContext(r.Context()))
+	if err != nil {
+		return err
+	}
+	filter.Page, filter.PageSize = 1, 20
+	if err := queryInt(vs, "page", &filter.Page); err != nil {
+		return err
+	}
+	if err := queryInt(vs, "page_size", &filter.PageSize); err != nil {
+		return err
+	}
+	queryList(vs, "fields", &filter.Fields)
+	queryList(vs, "facets", &filter.Facets)
+	if filter.VisibleTo, err = s.viewer(r.Context()); err != nil {
+		return err
 	}
 
-	vs := r.URL.Query()
+	if err := filter.Valid(); err != nil {
+		return err
+	}
+	include, err := s.movieInclude(vs)
+	if err != nil {
+		return err
+	}
 
-	filter.Title = vs.Get("title")
-	if vs.Has("genres") {
-		filter.Genres = strings.Split(vs.Get("genres"), ",")
+	ctx := greenlight.NewContextWithLanguages(r.Context(), requestLanguages(r))
+	movies, err := s.movieService.GetAll(ctx, filter)
+	if err != nil {
+		return err
 	}
-	if vs.Has("page") {
-		pageRaw := vs.Get("page")
-		page, err := strconv.Atoi(pageRaw)
-		if err != nil {
-			return greenlight.NewInvalidError(`Invalid "page" parameter format: %s`, pageRaw)
-		}
-		filter.Page = page
+
+	resp, err := s.movieResponses(r.Context(), movies, filter.Fields, include)
+	if err != nil {
+		return err
 	}
-	if vs.Has("page_size") {
-		pageSzRaw := vs.Get("page_size")
-		pageSz, err := strconv.Atoi(pageSzRaw)
-		if err != nil {
-			return greenlight.NewInvalidError(`Invalid "page_size" parameter format: %s`, pageSzRaw)
+
+	headers := make(http.Header)
+	headers.Set("Vary", "Accept-Language")
+
+	// Facets are only returned on request, wrapping the movies in an object.
+	if len(filter.Facets) == 0 {
+		if err := s.sendResponse(w, r, http.StatusOK, resp, headers); err != nil {
+			return err
 		}
-		filter.PageSize = pageSz
+		return nil
 	}
-	if vs.Has("sort") {
-		filter.Sort = vs.Get("sort")
+
+	facets, err := s.movieService.Facets(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+	facetsResp := struct {
+		Movies []any                  `json:"movies"`
+		Facets greenlight.MovieFacets `json:"facets"`
+	}{
+		Movies: resp,
+		Facets: facets,
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, facetsResp, headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieLookup handles requests to get a movie by its ID in an external catalogue.
+func (s *Server) handleMovieLookup(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieLookup")
+
+	vs := r.URL.Query()
+	source, id := vs.Get("source"), vs.Get("id")
+	e := greenlight.NewInvalidError("Lookup parameter(s) is/are invalid.")
+	if _, ok := greenlight.ExternalSources[source]; !ok {
+		e.AddViolationMsg("source", fmt.Sprintf("Unknown source %q.", source))
+	}
+	if id == "" {
+		e.AddViolationMsg("id", "Must be provided.")
+	}
+	if len(e.Violations()) != 0 {
+		return e
+	}
+
+	ctx := greenlight.NewContextWithLanguages(r.Context(), requestLanguages(r))
+	m, err := s.movieService.GetByExternalID(ctx, source, id)
+	if err != nil {
+		return err
+	}
+	viewer, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+	if !m.VisibleTo(viewer, time.Now()) {
+		return greenlight.ErrNotFound
+	}
+
+	resp, err := s.newMovieResponse(r.Context(), m)
+	if err != nil {
+		return err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Vary", "Accept-Language")
+	if m.Language != "" {
+		headers.Set("Content-Language", m.Language)
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, resp, headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMoviesSimilarGet handles requests to get movies similar to a specified movie.
+func (s *Server) handleMoviesSimilarGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMoviesSimilarGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	filter := greenlight.SimilarMovieFilter{
+		MovieID:  id,
+		Page:     1,
+		PageSize: 20,
+	}
+
+	vs := r.URL.Query()
+	if err := queryInt(vs, "page", &filter.Page); err != nil {
+		return err
+	}
+	if err := queryInt(vs, "page_size", &filter.PageSize); err != nil {
+		return err
+	}
+	queryList(vs, "fields", &filter.Fields)
+	if filter.VisibleTo, err = s.viewer(r.Context()); err != nil {
+		return err
 	}
 
 	if err := filter.Valid(); err != nil {
 		return err
 	}
+	include, err := s.movieInclude(vs)
+	if err != nil {
+		return err
+	}
 
-	movies, err := s.movieService.GetAll(r.Context(), filter)
+	ctx := greenlight.NewContextWithLanguages(r.Context(), requestLanguages(r))
+	movies, err := s.movieService.Similar(ctx, filter)
 	if err != nil {
 		return err
 	}
 
-	type respEl struct {
-		ID          int64    `json:"id"`
-		Title       string   `json:"title"`
-		ReleaseDate date     `json:"release_date,omitempty"`
-		Runtime     int      `json:"runtime,omitempty"`
-		Genres      []string `json:"genres,omitempty"`
+	resp, err := s.movieResponses(r.Context(), movies, filter.Fields, include)
+	if err != nil {
+		return err
 	}
-	resp := make([]*respEl, len(movies))
-	for i, m := range movies {
-		resp[i] = &respEl{
-			ID:          m.ID,
-			Title:       m.Title,
-			ReleaseDate: date(m.ReleaseDate),
-			Runtime:     m.Runtime,
-			Genres:      m.Genres,
+
+	headers := make(http.Header)
+	headers.Set("Vary", "Accept-Language")
+	if err := s.sendResponse(w, r, http.StatusOK, resp, headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMoviesExport handles requests to export all the movies matching the filter parameters.
+// The movies are streamed in the format specified by the "format" query parameter, JSON by default.
+func (s *Server) handleMoviesExport(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMoviesExport")
+
+	vs := r.URL.Query()
+	filter, err := queryMovieFilter(vs, greenlight.UserIDFromContext(r.Context()))
+	if err != nil {
+		return err
+	}
+	// Paging parameters are ignored by the export, but must be valid.
+	filter.Page, filter.PageSize = 1, 1
+	if filter.VisibleTo, err = s.viewer(r.Context()); err != nil {
+		return err
+	}
+	if err := filter.Valid(); err != nil {
+		return err
+	}
+
+	format := movieio.JSON
+	if vs.Has("format") {
+		if format, err = movieio.ParseFormat(vs.Get("format")); err != nil {
+			return err
 		}
 	}
 
-	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+	// The export may outlast the server write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		return err
+	}
+
+	ew := &exportWriter{w: w}
+	mw, err := movieio.NewWriter(ew, format)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="movies.%s"`, format))
+
+	err = s.movieService.Export(r.Context(), filter, mw.Write)
+	if err == nil {
+		err = mw.Close()
+	}
+	if err != nil && !ew.written {
 		return err
 	}
+	// The status is already sent, so the error can only be logged. The client gets a truncated response.
+	if err != nil {
+		s.LogError(w, r, "Exporting movies", err)
+	}
 	return nil
 }
 
+// exportWriter records whether the response body has been written to.
+type exportWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.w.Write(p)
+}
+
 // handleMovieCreate handles requests to create a movie.
 func (s *Server) handleMovieCreate(w http.ResponseWriter, r *http.Request) (err error) {
 	defer multierr.Wrap(&err, "http.Server.handleMovieCreate")
 
 	var req struct {
-		Title       string   `json:"title"`
-		ReleaseDate date     `json:"release_date"`
-		Runtime     int      `json:"runtime"`
-		Genres      []string `json:"genres"`
+		Title          string            `json:"title"`
+		ReleaseDate    date              `json:"release_date"`
+		Runtime        int               `json:"runtime"`
+		Genres         []string          `json:"genres"`
+		ExternalIDs    map[string]string `json:"external_ids"`
+		Certifications map[string]string `json:"certifications"`
 	}
 	if err := s.readRequest(w, r, &req); err != nil {
 		return err
 	}
 
 	m := &greenlight.Movie{
-		Title:       req.Title,
-		ReleaseDate: time.Time(req.ReleaseDate),
-		Runtime:     req.Runtime,
-		Genres:      req.Genres,
+		Title:          req.Title,
+		ReleaseDate:    time.Time(req.ReleaseDate),
+		Runtime:        req.Runtime,
+		Genres:         req.Genres,
+		ExternalIDs:    req.ExternalIDs,
+		Certifications: req.Certifications,
 	}
 	if err := m.Valid(); err != nil {
 		return err
 	}
-	if err := s.movieService.Create(r.Context(), m); err != nil {
+
+	// The "force" query parameter skips the duplicate detection.
+	mode := s.opts.duplicateMode
+	if force, _ := strconv.ParseBool(r.URL.Query().Get("force")); force || mode == "" {
+		mode = greenlight.DuplicatesOff
+	}
+	ctx := greenlight.NewContextWithDuplicateMode(r.Context(), mode)
+	if err := s.movieService.Create(ctx, m); err != nil {
 		return err
 	}
 
-	resp := struct {
-		ID          int64    `json:"id"`
-		Title       string   `json:"title"`
-		ReleaseDate date     `json:"release_date,omitempty"`
-		Runtime     int      `json:"runtime,omitempty"`
-		Genres      []string `json:"genres,omitempty"`
-	}{
-		ID:          m.ID,
-		Title:       m.Title,
-		ReleaseDate: date(m.ReleaseDate),
-		Runtime:     m.Runtime,
-		Genres:      m.Genres,
+	resp, err := s.newMovieResponse(r.Context(), m)
+	if err != nil {
+		return err
+	}
+	if len(m.Duplicates) != 0 {
+		resp.Warnings = append(resp.Warnings, movieWarning{
+			Msg: "The movie is likely a duplicate of existing movies.",
+			IDs: m.Duplicates,
+		})
 	}
 
 	headers := make(http.Header)
@@ -175,6 +384,37 @@ func (s *Server) handleMovieCreate(w http.ResponseWriter, r *http.Request) (err
 	return nil
 }
 
+// handleMoviesImport handles requests to import movies in bulk from a CSV or NDJSON request body.
+// The format is specified by the "format" query parameter or the Content-Type header.
+// The imported movies are drafts of the authenticated user, like the created ones.
+func (s *Server) handleMoviesImport(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMoviesImport")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = r.Header.Get("Content-Type")
+	}
+	f, err := movieio.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.opts.maxImportSize)
+	report, err := movieio.NewImporter(s.movieService, s.opts.genreService).Import(r.Context(), r.Body, f, greenlight.MovieDraft)
+	if err != nil {
+		// Batches read before the limit was exceeded are already imported.
+		if errors.As(err, new(*http.MaxBytesError)) && report != nil {
+			return greenlight.NewInvalidError("Imported data must not be larger than %d bytes, %d movie(s) were imported.", s.opts.maxImportSize, report.Imported)
+		}
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, report, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
 // handleMovieUpdate handles requests to update a specified movie.
 func (s *Server) handleMovieUpdate(w http.ResponseWriter, r *http.Request) (err error) {
 	defer multierr.Wrap(&err, "http.Server.handleMovieUpdate")
@@ -189,13 +429,50 @@ func (s *Server) handleMovieUpdate(w http.ResponseWriter, r *http.Request) (err
 	if err != nil {
 		return err
 	}
+	if err := s.authorizeMovieEdit(r.Context(), m); err != nil {
+		return err
+	}
+
+	switch ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct {
+	case mergePatchContentType, jsonPatchContentType:
+		if err := s.patchMovie(w, r, m, ct); err != nil {
+			return err
+		}
+	default:
+		if err := s.updateMovie(w, r, m); err != nil {
+			return err
+		}
+	}
+
+	if err := m.Valid(); err != nil {
+		return err
+	}
+	if err := s.movieService.Update(r.Context(), m); err != nil {
+		return err
+	}
+
+	resp, err := s.newMovieResponse(r.Context(), m)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
 
+// updateMovie updates the movie with the fields of the request body.
+func (s *Server) updateMovie(w http.ResponseWriter, r *http.Request, m *greenlight.Movie) error {
 	// use pointers to allow partial updates
+	// External IDs and certifications are merged into the existing ones, a null value removes the one of the source or country.
 	var req struct {
-		Title       *string  `json:"title"`
-		ReleaseDate *date    `json:"release_date"`
-		Runtime     *int     `json:"runtime"`
-		Genres      []string `json:"genres"`
+		Title          *string            `json:"title"`
+		ReleaseDate    *date              `json:"release_date"`
+		Runtime        *int               `json:"runtime"`
+		Genres         []string           `json:"genres"`
+		ExternalIDs    map[string]*string `json:"external_ids"`
+		Certifications map[string]*string `json:"certifications"`
 	}
 	if err := s.readRequest(w, r, &req); err != nil {
 		return err
@@ -213,31 +490,97 @@ func (s *Server) handleMovieUpdate(w http.ResponseWriter, r *http.Request) (err
 	if req.Genres != nil {
 		m.Genres = req.Genres
 	}
+	for src, id := range req.ExternalIDs {
+		if m.ExternalIDs == nil {
+			m.ExternalIDs = make(map[string]string)
+		}
+		if id == nil {
+			delete(m.ExternalIDs, src)
+		} else {
+			m.ExternalIDs[src] = *id
+		}
+	}
+	for country, rating := range req.Certifications {
+		if m.Certifications == nil {
+			m.Certifications = make(map[string]string)
+		}
+		if rating == nil {
+			delete(m.Certifications, country)
+		} else {
+			m.Certifications[country] = *rating
+		}
+	}
+	return nil
+}
 
-	if err := m.Valid(); err != nil {
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// moviePatchDocument represents the JSON document of a movie that patches are applied to.
+type moviePatchDocument struct {
+	Title          string            `json:"title"`
+	ReleaseDate    *date             `json:"release_date"`
+	Runtime        int               `json:"runtime"`
+	Genres         []string          `json:"genres"`
+	ExternalIDs    map[string]string `json:"external_ids"`
+	Certifications map[string]string `json:"certifications"`
+}
+
+// patchMovie applies the JSON Merge Patch or the JSON Patch of the request body, depending on the content type, to the movie.
+// Removing a member of the document clears the field of the movie.
+func (s *Server) patchMovie(w http.ResponseWriter, r *http.Request, m *greenlight.Movie, contentType string) error {
+	var patch json.RawMessage
+	if err := s.readRequest(w, r, &patch); err != nil {
 		return err
 	}
-	if err := s.movieService.Update(r.Context(), m); err != nil {
+
+	d := date(m.ReleaseDate)
+	doc, err := json.Marshal(moviePatchDocument{
+		Title:          m.Title,
+		ReleaseDate:    &d,
+		Runtime:        m.Runtime,
+		Genres:         m.Genres,
+		ExternalIDs:    m.ExternalIDs,
+		Certifications: m.Certifications,
+	})
+	if err != nil {
 		return err
 	}
 
-	resp := struct {
-		ID          int64    `json:"id"`
-		Title       string   `json:"title"`
-		ReleaseDate date     `json:"release_date,omitempty"`
-		Runtime     int      `json:"runtime,omitempty"`
-		Genres      []string `json:"genres,omitempty"`
-	}{
-		ID:          m.ID,
-		Title:       m.Title,
-		ReleaseDate: date(m.ReleaseDate),
-		Runtime:     m.Runtime,
-		Genres:      m.Genres,
+	if contentType == mergePatchContentType {
+		doc, err = jsonpatch.MergePatch(doc, patch)
+	} else {
+		doc, err = jsonpatch.Apply(doc, patch)
+	}
+	switch {
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		return greenlight.NewConflictError("Patch is not applicable to the current movie: %v.", err)
+	case err != nil:
+		return greenlight.NewInvalidError("Patch is invalid: %v.", err)
 	}
 
-	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
-		return err
+	var patched moviePatchDocument
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patched); err != nil {
+		var ie *greenlight.InvalidError
+		if errors.As(err, &ie) {
+			return ie
+		}
+		return greenlight.NewInvalidError("Patched movie is invalid: %v.", err)
 	}
+
+	m.Title = patched.Title
+	m.ReleaseDate = time.Time{}
+	if patched.ReleaseDate != nil {
+		m.ReleaseDate = time.Time(*patched.ReleaseDate)
+	}
+	m.Runtime = patched.Runtime
+	m.Genres = patched.Genres
+	m.ExternalIDs = patched.ExternalIDs
+	m.Certifications = patched.Certifications
 	return nil
 }
 
@@ -251,6 +594,14 @@ func (s *Server) handleMovieDelete(w http.ResponseWriter, r *http.Request) (err
 		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
 	}
 
+	m, err := s.movieService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeMovieEdit(r.Context(), m); err != nil {
+		return err
+	}
+
 	if err := s.movieService.Delete(r.Context(), id); err != nil {
 		return err
 	}
@@ -260,6 +611,338 @@ func (s *Server) handleMovieDelete(w http.ResponseWriter, r *http.Request) (err
 	return nil
 }
 
+// handleMovieTranslationsGet handles requests to get the translations of a specified movie.
+func (s *Server) handleMovieTranslationsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieTranslationsGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	if _, err := s.visibleMovie(r.Context(), id); err != nil {
+		return err
+	}
+	ts, err := s.movieService.Translations(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if ts == nil {
+		ts = []*greenlight.MovieTranslation{}
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, ts, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieTranslationPut handles requests to create or replace a translation of a specified movie.
+func (s *Server) handleMovieTranslationPut(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieTranslationPut")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	var req struct {
+		Title    string `json:"title"`
+		Synopsis string `json:"synopsis"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	m, err := s.movieService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeMovieEdit(r.Context(), m); err != nil {
+		return err
+	}
+
+	t := &greenlight.MovieTranslation{
+		Language: greenlight.NormalizeLanguage(r.PathValue("lang")),
+		Title:    req.Title,
+		Synopsis: req.Synopsis,
+	}
+	if err := t.Valid(); err != nil {
+		return err
+	}
+	if err := s.movieService.SetTranslation(r.Context(), id, t); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, t, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieTranslationDelete handles requests to delete a translation of a specified movie.
+func (s *Server) handleMovieTranslationDelete(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieTranslationDelete")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	m, err := s.movieService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeMovieEdit(r.Context(), m); err != nil {
+		return err
+	}
+
+	if err := s.movieService.DeleteTranslation(r.Context(), id, greenlight.NormalizeLanguage(r.PathValue("lang"))); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieStatusUpdate handles requests to change the status of a specified movie in the editorial workflow.
+func (s *Server) handleMovieStatusUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieStatusUpdate")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	var req struct {
+		Status    string     `json:"status"`
+		PublishAt *time.Time `json:"publish_at"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	viewer, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+	m, err := s.movieService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if !m.VisibleTo(viewer, time.Now()) {
+		return greenlight.ErrNotFound
+	}
+
+	var publishAt time.Time
+	if req.PublishAt != nil {
+		publishAt = *req.PublishAt
+	}
+	if err := m.Transition(greenlight.MovieStatus(req.Status), publishAt, viewer); err != nil {
+		return err
+	}
+	if err := m.Valid(); err != nil {
+		return err
+	}
+	if err := s.movieService.Update(r.Context(), m); err != nil {
+		return err
+	}
+
+	resp, err := s.newMovieResponse(r.Context(), m)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// viewer returns the authenticated user, or the zero user for anonymous requests.
+func (s *Server) viewer(ctx context.Context) (*greenlight.User, error) {
+	userID := greenlight.UserIDFromContext(ctx)
+	if userID == 0 {
+		return &greenlight.User{}, nil
+	}
+	u, err := s.userService.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, greenlight.ErrNotFound) {
+			return nil, greenlight.NewUnauthorizedError("Invalid or missing authentication token.")
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// visibleMovie returns the movie if it's visible to the authenticated user, see [greenlight.Movie.VisibleTo].
+func (s *Server) visibleMovie(ctx context.Context, id int64) (*greenlight.Movie, error) {
+	viewer, err := s.viewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.movieService.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !m.VisibleTo(viewer, time.Now()) {
+		return nil, greenlight.ErrNotFound
+	}
+	return m, nil
+}
+
+// authorizeMovieEdit returns an error unless the authenticated user can update or delete the movie.
+// See [greenlight.Movie.CanEdit].
+func (s *Server) authorizeMovieEdit(ctx context.Context, m *greenlight.Movie) error {
+	u, err := s.viewer(ctx)
+	if err != nil {
+		return err
+	}
+	if !m.CanEdit(u.ID, u.Role) {
+		return greenlight.NewForbiddenError("You can only change the movies you created.")
+	}
+	return nil
+}
+
+// movieResponse represents a movie sent to clients.
+type movieResponse struct {
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Synopsis    string   `json:"synopsis,omitempty"`
+	ReleaseDate date     `json:"release_date,omitempty"`
+	Runtime     int      `json:"runtime,omitempty"`
+	Genres      []string `json:"genres,omitempty"`
+	// Poster are URLs of the poster images keyed by the image name.
+	Poster map[string]string `json:"poster,omitempty"`
+	Rating float64           `json:"rating"`
+	Votes  int               `json:"votes"`
+	// ExternalIDs are IDs of the movie in the external catalogues keyed by the catalogue.
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`
+	// Certifications are the age certification ratings of the movie keyed by the country.
+	Certifications map[string]string `json:"certifications,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	PublishAt      *time.Time        `json:"publish_at,omitempty"`
+	// Warnings are warnings about the request that succeeded nonetheless.
+	Warnings []movieWarning `json:"warnings,omitempty"`
+}
+
+// movieWarning represents a warning about a movie request.
+type movieWarning struct {
+	Msg string `json:"message"`
+	// IDs are IDs of the movies the warning is about.
+	IDs []int64 `json:"movie_ids,omitempty"`
+}
+
+func (s *Server) newMovieResponse(ctx context.Context, m *greenlight.Movie) (*movieResponse, error) {
+	poster, err := s.posterURLs(ctx, m.Poster)
+	if err != nil {
+		return nil, err
+	}
+	resp := &movieResponse{
+		ID:             m.ID,
+		Title:          m.Title,
+		Synopsis:       m.Synopsis,
+		ReleaseDate:    date(m.ReleaseDate),
+		Runtime:        m.Runtime,
+		Genres:         m.Genres,
+		Poster:         poster,
+		Rating:         m.Rating,
+		Votes:          m.Votes,
+		ExternalIDs:    m.ExternalIDs,
+		Certifications: m.Certifications,
+		Tags:           m.Tags,
+		Status:         string(m.Status),
+	}
+	if !m.PublishAt.IsZero() {
+		resp.PublishAt = &m.PublishAt
+	}
+	return resp, nil
+}
+
+// movieIncluder loads a relation of the movies to embed in the responses.
+// Returned values are keyed by the movie ID; movies without a value get a JSON null.
+type movieIncluder func(ctx context.Context, movies []*greenlight.Movie) (map[int64]any, error)
+
+// movieIncluders returns the relations that can be embedded in movie responses using the "include" query parameter.
+func (s *Server) movieIncluders() map[string]movieIncluder {
+	return map[string]movieIncluder{
+		"credits": s.includeCredits,
+	}
+}
+
+// movieInclude returns the names of the relations requested by the "include" query parameter.
+func (s *Server) movieInclude(vs url.Values) ([]string, error) {
+	if !vs.Has("include") {
+		return nil, nil
+	}
+	include := strings.Split(vs.Get("include"), ",")
+
+	incs := s.movieIncluders()
+	for _, name := range include {
+		if _, ok := incs[name]; !ok {
+			err := greenlight.NewInvalidError("Movie parameter(s) is/are invalid.")
+			err.AddViolationMsg("include", fmt.Sprintf("Unknown relation %q.", name))
+			return nil, err
+		}
+	}
+	return include, nil
+}
+
+// movieResponses returns responses for the movies.
+// If fields are provided, responses only contain those fields. Included relations are embedded under their names.
+func (s *Server) movieResponses(ctx context.Context, movies []*greenlight.Movie, fields, include []string) ([]any, error) {
+	resp := make([]any, len(movies))
+	for i, m := range movies {
+		mr, err := s.newMovieResponse(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		resp[i] = mr
+	}
+	if len(fields) == 0 && len(include) == 0 {
+		return resp, nil
+	}
+
+	incs := s.movieIncluders()
+	embeds := make(map[string]map[int64]any, len(include))
+	for _, name := range include {
+		vs, err := incs[name](ctx, movies)
+		if err != nil {
+			return nil, err
+		}
+		embeds[name] = vs
+	}
+
+	for i, m := range movies {
+		js, err := json.Marshal(resp[i])
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(js, &all); err != nil {
+			return nil, err
+		}
+
+		el := make(map[string]any, len(all)+len(embeds))
+		for k, v := range all {
+			if len(fields) == 0 || slices.Contains(fields, k) {
+				el[k] = v
+			}
+		}
+		for name, vs := range embeds {
+			el[name] = vs[m.ID]
+		}
+		resp[i] = el
+	}
+	return resp, nil
+}
+
 // date represents a date in the format "YYYY-MM-DD".
 type date time.Time
 
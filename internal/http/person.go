@@ -0,0 +1,381 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerPersonHandlers() {
+	s.router.Handle("GET /v1/people/{id}", s.handlerFunc(s.handlePersonGet))
+	s.router.Handle("GET /v1/people", s.handlerFunc(s.handlePeopleGet))
+	s.router.Handle("POST /v1/people", s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(s.handlePersonCreate))))
+	s.router.Handle("PATCH /v1/people/{id}", s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(s.handlePersonUpdate))))
+	s.router.Handle("DELETE /v1/people/{id}", s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(s.handlePersonDelete))))
+	s.router.Handle("GET /v1/people/{id}/filmography", s.authenticateOptional(s.handlerFunc(s.handleFilmographyGet)))
+	s.router.Handle("GET /v1/movies/{id}/credits", s.authenticateOptional(s.handlerFunc(s.handleMovieCreditsGet)))
+	s.router.Handle("PUT /v1/movies/{id}/credits", s.authenticate(s.handlerFunc(s.handleMovieCreditsUpdate)))
+}
+
+// handlePersonGet handles requests to get a specified person.
+func (s *Server) handlePersonGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handlePersonGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	p, err := s.opts.personService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newPersonResponse(p), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handlePeopleGet handles requests to get people based on provided filter parameters.
+func (s *Server) handlePeopleGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handlePeopleGet")
+
+	filter := greenlight.PersonFilter{
+		Name:     "",
+		Page:     1,
+		PageSize: 20,
+		Sort:     "id",
+	}
+
+	vs := r.URL.Query()
+
+	filter.Name = vs.Get("name")
+	if err := queryInt(vs, "page", &filter.Page); err != nil {
+		return err
+	}
+	if err := queryInt(vs, "page_size", &filter.PageSize); err != nil {
+		return err
+	}
+	if vs.Has("sort") {
+		filter.Sort = vs.Get("sort")
+	}
+
+	if err := filter.Valid(); err != nil {
+		return err
+	}
+
+	people, err := s.opts.personService.GetAll(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*personResponse, len(people))
+	for i, p := range people {
+		resp[i] = newPersonResponse(p)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handlePersonCreate handles requests to create a person.
+func (s *Server) handlePersonCreate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handlePersonCreate")
+
+	var req struct {
+		Name      string `json:"name"`
+		BirthDate date   `json:"birth_date"`
+		Biography string `json:"biography"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	p := &greenlight.Person{
+		Name:      req.Name,
+		BirthDate: time.Time(req.BirthDate),
+		Biography: req.Biography,
+	}
+	if err := p.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.personService.Create(r.Context(), p); err != nil {
+		return err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/people/%d", p.ID))
+	if err := s.sendResponse(w, r, http.StatusCreated, newPersonResponse(p), headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handlePersonUpdate handles requests to update a specified person.
+func (s *Server) handlePersonUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handlePersonUpdate")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	p, err := s.opts.personService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	// use pointers to allow partial updates
+	var req struct {
+		Name      *string `json:"name"`
+		BirthDate *date   `json:"birth_date"`
+		Biography *string `json:"biography"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.Name != nil {
+		p.Name = *req.Name
+	}
+	if req.BirthDate != nil {
+		p.BirthDate = time.Time(*req.BirthDate)
+	}
+	if req.Biography != nil {
+		p.Biography = *req.Biography
+	}
+
+	if err := p.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.personService.Update(r.Context(), p); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newPersonResponse(p), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handlePersonDelete handles requests to delete a specified person.
+func (s *Server) handlePersonDelete(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handlePersonDelete")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	if err := s.opts.personService.Delete(r.Context(), id); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleFilmographyGet handles requests to get the credits of a specified person.
+func (s *Server) handleFilmographyGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleFilmographyGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	viewer, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+	credits, err := s.opts.personService.Filmography(r.Context(), id, viewer)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*creditResponse, len(credits))
+	for i, c := range credits {
+		if resp[i], err = s.newCreditResponse(r.Context(), c); err != nil {
+			return err
+		}
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieCreditsGet handles requests to get the credits of a specified movie.
+func (s *Server) handleMovieCreditsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieCreditsGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	m, err := s.visibleMovie(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	credits, err := s.includeCredits(r.Context(), []*greenlight.Movie{m})
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, credits[m.ID], nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieCreditsUpdate handles requests to replace the credits of a specified movie.
+func (s *Server) handleMovieCreditsUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieCreditsUpdate")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	m, err := s.movieService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeMovieEdit(r.Context(), m); err != nil {
+		return err
+	}
+
+	var req []struct {
+		PersonID  int64  `json:"person_id"`
+		Role      string `json:"role"`
+		Character string `json:"character"`
+		Billing   int    `json:"billing"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	credits := make([]*greenlight.Credit, len(req))
+	for i, c := range req {
+		credits[i] = &greenlight.Credit{
+			MovieID:   id,
+			PersonID:  c.PersonID,
+			Role:      greenlight.CreditRole(c.Role),
+			Character: c.Character,
+			Billing:   c.Billing,
+		}
+		if err := credits[i].Valid(); err != nil {
+			return err
+		}
+	}
+	if err := s.opts.personService.SetMovieCredits(r.Context(), id, credits); err != nil {
+		return err
+	}
+
+	resp, err := s.includeCredits(r.Context(), []*greenlight.Movie{m})
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp[m.ID], nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// includeCredits returns the credits of the movies. It is a [movieIncluder].
+func (s *Server) includeCredits(ctx context.Context, movies []*greenlight.Movie) (map[int64]any, error) {
+	ids := make([]int64, len(movies))
+	for i, m := range movies {
+		ids[i] = m.ID
+	}
+	credits, err := s.opts.personService.MovieCredits(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	byMovie := make(map[int64][]*creditResponse, len(movies))
+	for _, id := range ids {
+		byMovie[id] = []*creditResponse{}
+	}
+	for _, c := range credits {
+		cr, err := s.newCreditResponse(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		byMovie[c.MovieID] = append(byMovie[c.MovieID], cr)
+	}
+
+	resp := make(map[int64]any, len(byMovie))
+	for id, crs := range byMovie {
+		resp[id] = crs
+	}
+	return resp, nil
+}
+
+// personResponse represents a person sent to clients.
+type personResponse struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	BirthDate *date  `json:"birth_date,omitempty"`
+	Biography string `json:"biography,omitempty"`
+}
+
+func newPersonResponse(p *greenlight.Person) *personResponse {
+	resp := &personResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		Biography: p.Biography,
+	}
+	if !p.BirthDate.IsZero() {
+		d := date(p.BirthDate)
+		resp.BirthDate = &d
+	}
+	return resp
+}
+
+// creditResponse represents a credit sent to clients.
+// It contains either a person, for the movie credits, or a movie, for the person filmography.
+type creditResponse struct {
+	Person    *personResponse `json:"person,omitempty"`
+	Movie     *movieResponse  `json:"movie,omitempty"`
+	Role      string          `json:"role"`
+	Character string          `json:"character,omitempty"`
+	Billing   int             `json:"billing"`
+}
+
+func (s *Server) newCreditResponse(ctx context.Context, c *greenlight.Credit) (*creditResponse, error) {
+	resp := &creditResponse{
+		Role:      string(c.Role),
+		Character: c.Character,
+		Billing:   c.Billing,
+	}
+	if c.Person != nil {
+		resp.Person = newPersonResponse(c.Person)
+	}
+	if c.Movie != nil {
+		mr, err := s.newMovieResponse(ctx, c.Movie)
+		if err != nil {
+			return nil, err
+		}
+		resp.Movie = mr
+	}
+	return resp, nil
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+// posterURLTTL is the time poster URLs sent to clients are valid for.
+const posterURLTTL = 24 * time.Hour
+
+func (s *Server) registerPosterHandlers() {
+	s.router.Handle("PUT /v1/movies/{id}/poster", s.authenticate(s.handlerFunc(s.handlePosterUpdate)))
+}
+
+// handlePosterUpdate handles requests to upload a poster of a specified movie.
+// The poster is sent as the "poster" part of a multipart form.
+func (s *Server) handlePosterUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handlePosterUpdate")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	m, err := s.movieService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeMovieEdit(r.Context(), m); err != nil {
+		return err
+	}
+
+	data, err := s.readPoster(w, r)
+	if err != nil {
+		return err
+	}
+	imgs, err := greenlight.NewPosterImages(data)
+	if err != nil {
+		return err
+	}
+
+	// Images are stored under a new prefix, so the old poster stays intact until the movie is updated.
+	var rnd [8]byte
+	if _, err := rand.Read(rnd[:]); err != nil {
+		return err
+	}
+	prefix := fmt.Sprintf("posters/%d/%s", m.ID, hex.EncodeToString(rnd[:]))
+	keys := make(map[string]string, len(imgs))
+	for name, img := range imgs {
+		exts, _ := mime.ExtensionsByType(img.ContentType)
+		ext := ".jpg"
+		if img.ContentType != "image/jpeg" && len(exts) != 0 {
+			ext = exts[0]
+		}
+		key := prefix + "/" + name + ext
+		if err := s.opts.blobStore.Put(r.Context(), key, bytes.NewReader(img.Data), img.ContentType); err != nil {
+			return err
+		}
+		keys[name] = key
+	}
+
+	old := m.Poster
+	m.Poster = keys
+	if err := s.movieService.Update(r.Context(), m); err != nil {
+		s.deletePoster(r.Context(), w, r, keys)
+		return err
+	}
+	s.deletePoster(r.Context(), w, r, old)
+
+	resp, err := s.newMovieResponse(r.Context(), m)
+	if err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readPoster returns the content of the "poster" part of the multipart request body.
+func (s *Server) readPoster(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.opts.maxPosterSize)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, greenlight.NewInvalidError("Request body must be a multipart form.")
+	}
+	for {
+		p, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, greenlight.NewInvalidError(`Request body must contain a "poster" part.`)
+		}
+		if err != nil {
+			return nil, greenlight.NewInvalidError("Multipart form format is incorrect.")
+		}
+		if p.FormName() != "poster" {
+			continue
+		}
+
+		switch ct := p.Header.Get("Content-Type"); ct {
+		case "", "application/octet-stream", "image/jpeg", "image/png", "image/gif":
+		default:
+			return nil, greenlight.NewInvalidError("Unsupported poster content type: %s", ct)
+		}
+
+		data, err := io.ReadAll(p)
+		if err != nil {
+			if errors.As(err, new(*http.MaxBytesError)) {
+				return nil, greenlight.NewInvalidError("Poster must not be larger than %d bytes.", s.opts.maxPosterSize)
+			}
+			return nil, err
+		}
+		return data, nil
+	}
+}
+
+// deletePoster deletes the poster images from the blob store. Errors are logged, since the images are unreferenced.
+func (s *Server) deletePoster(ctx context.Context, w http.ResponseWriter, r *http.Request, keys map[string]string) {
+	for _, key := range keys {
+		if err := s.opts.blobStore.Delete(ctx, key); err != nil {
+			s.LogError(w, r, "Deleting poster image", err)
+		}
+	}
+}
+
+// posterURLs returns the URLs of the poster images keyed by the image name.
+// Without a blob store the posters aren't served, so none are returned.
+func (s *Server) posterURLs(ctx context.Context, keys map[string]string) (map[string]string, error) {
+	if len(keys) == 0 || s.opts.blobStore == nil {
+		return nil, nil
+	}
+	urls := make(map[string]string, len(keys))
+	for name, key := range keys {
+		u, err := s.opts.blobStore.URL(ctx, key, posterURLTTL)
+		if err != nil {
+			return nil, err
+		}
+		urls[name] = u
+	}
+	return urls, nil
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+// queryInt parses an integer query parameter into dst.
+// If the parameter is not present, dst is left unchanged.
+func queryInt(vs url.Values, key string, dst *int) error {
+	if !vs.Has(key) {
+		return nil
+	}
+	raw := vs.Get(key)
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return greenlight.NewInvalidError(`Invalid "%s" parameter format: %s`, key, raw)
+	}
+	*dst = v
+	return nil
+}
+
+// queryInt64 parses a 64-bit integer query parameter into dst.
+// If the parameter is not present, dst is left unchanged.
+func queryInt64(vs url.Values, key string, dst *int64) error {
+	if !vs.Has(key) {
+		return nil
+	}
+	raw := vs.Get(key)
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return greenlight.NewInvalidError(`Invalid "%s" parameter format: %s`, key, raw)
+	}
+	*dst = v
+	return nil
+}
+
+// queryDate parses a query parameter in the format "YYYY-MM-DD" into dst.
+// If the parameter is not present, dst is left unchanged.
+func queryDate(vs url.Values, key string, dst *time.Time) error {
+	if !vs.Has(key) {
+		return nil
+	}
+	raw := vs.Get(key)
+	v, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return greenlight.NewInvalidError(`Invalid "%s" parameter format: %s`, key, raw)
+	}
+	*dst = v
+	return nil
+}
+
+// queryCertification parses a certification query parameter in the format "COUNTRY:RATING" into dst.
+// If the parameter is not present, dst is left unchanged.
+func queryCertification(vs url.Values, key string, dst *greenlight.Certification) error {
+	if !vs.Has(key) {
+		return nil
+	}
+	raw := vs.Get(key)
+	v, err := greenlight.ParseCertification(raw)
+	if err != nil {
+		return greenlight.NewInvalidError(`Invalid "%s" parameter format: %s`, key, raw)
+	}
+	*dst = v
+	return nil
+}
+
+// queryList parses a comma-separated list query parameter into dst.
+// If the parameter is not present, dst is left unchanged.
+func queryList(vs url.Values, key string, dst *[]string) {
+	if !vs.Has(key) {
+		return
+	}
+	*dst = strings.Split(vs.Get(key), ",")
+}
+
+// queryMovieFilter parses the movie filter query parameters, except the paging, fields and facets ones.
+// The "mine" parameter filters the movies created by the user, so it requires the user to be authenticated.
+func queryMovieFilter(vs url.Values, userID int64) (greenlight.MovieFilter, error) {
+	filter := greenlight.MovieFilter{
+		Title:  "",
+		Genres: []string{},
+		Sort:   "id",
+	}
+
+	filter.Title = vs.Get("title")
+	queryList(vs, "genres", &filter.Genres)
+	filter.GenresMode = vs.Get("genres_mode")
+	queryList(vs, "tags", &filter.Tags)
+	if err := queryDate(vs, "release_date_from", &filter.ReleaseDateFrom); err != nil {
+		return filter, err
+	}
+	if err := queryDate(vs, "release_date_to", &filter.ReleaseDateTo); err != nil {
+		return filter, err
+	}
+	if err := queryInt(vs, "year", &filter.Year); err != nil {
+		return filter, err
+	}
+	if err := queryInt(vs, "runtime_min", &filter.RuntimeMin); err != nil {
+		return filter, err
+	}
+	if err := queryInt(vs, "runtime_max", &filter.RuntimeMax); err != nil {
+		return filter, err
+	}
+	if vs.Has("sort") {
+		filter.Sort = vs.Get("sort")
+	}
+	if err := queryInt64(vs, "person_id", &filter.PersonID); err != nil {
+		return filter, err
+	}
+	filter.Status = greenlight.MovieStatus(vs.Get("status"))
+	if err := queryCertification(vs, "certification", &filter.Certification); err != nil {
+		return filter, err
+	}
+	if err := queryCertification(vs, "certification_max", &filter.MaxCertification); err != nil {
+		return filter, err
+	}
+	if mine, _ := strconv.ParseBool(vs.Get("mine")); mine {
+		if userID == 0 {
+			return filter, greenlight.NewUnauthorizedError("You must be authenticated to list your movies.")
+		}
+		filter.CreatedBy = userID
+	}
+	return filter, nil
+}
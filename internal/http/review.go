@@ -0,0 +1,234 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerReviewHandlers() {
+	s.router.Handle("GET /v1/movies/{id}/reviews", s.authenticateOptional(s.handlerFunc(s.handleReviewsGet)))
+	s.router.Handle("POST /v1/movies/{id}/reviews", s.authenticate(s.handlerFunc(s.handleReviewCreate)))
+	s.router.Handle("GET /v1/reviews/{id}", s.authenticateOptional(s.handlerFunc(s.handleReviewGet)))
+	s.router.Handle("PATCH /v1/reviews/{id}", s.authenticate(s.handlerFunc(s.handleReviewUpdate)))
+	s.router.Handle("DELETE /v1/reviews/{id}", s.authenticate(s.handlerFunc(s.handleReviewDelete)))
+}
+
+// handleReviewGet handles requests to get a specified review.
+func (s *Server) handleReviewGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleReviewGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	rv, err := s.opts.reviewService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.visibleMovie(r.Context(), rv.MovieID); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newReviewResponse(rv), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleReviewsGet handles requests to get reviews of a specified movie.
+func (s *Server) handleReviewsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleReviewsGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	filter := greenlight.ReviewFilter{
+		MovieID:  id,
+		Page:     1,
+		PageSize: 20,
+		Sort:     "-created_at",
+	}
+
+	vs := r.URL.Query()
+	if err := queryInt(vs, "page", &filter.Page); err != nil {
+		return err
+	}
+	if err := queryInt(vs, "page_size", &filter.PageSize); err != nil {
+		return err
+	}
+	if vs.Has("sort") {
+		filter.Sort = vs.Get("sort")
+	}
+
+	if err := filter.Valid(); err != nil {
+		return err
+	}
+
+	// Respond with 404 Not Found for non-existing movies rather than with an empty list.
+	if _, err := s.visibleMovie(r.Context(), id); err != nil {
+		return err
+	}
+	reviews, err := s.opts.reviewService.GetAll(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*reviewResponse, len(reviews))
+	for i, rv := range reviews {
+		resp[i] = newReviewResponse(rv)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleReviewCreate handles requests to review a specified movie by the authenticated user.
+func (s *Server) handleReviewCreate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleReviewCreate")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	var req struct {
+		Rating int    `json:"rating"`
+		Text   string `json:"text"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	rv := &greenlight.Review{
+		MovieID: id,
+		UserID:  greenlight.UserIDFromContext(r.Context()),
+		Rating:  req.Rating,
+		Text:    req.Text,
+	}
+	if err := rv.Valid(); err != nil {
+		return err
+	}
+	if _, err := s.visibleMovie(r.Context(), id); err != nil {
+		return err
+	}
+	if err := s.opts.reviewService.Create(r.Context(), rv); err != nil {
+		return err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/reviews/%d", rv.ID))
+	if err := s.sendResponse(w, r, http.StatusCreated, newReviewResponse(rv), headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleReviewUpdate handles requests to update a specified review of the authenticated user.
+func (s *Server) handleReviewUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleReviewUpdate")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	rv, err := s.opts.reviewService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if rv.UserID != greenlight.UserIDFromContext(r.Context()) {
+		return greenlight.NewForbiddenError("You can only change your own reviews.")
+	}
+
+	// use pointers to allow partial updates
+	var req struct {
+		Rating *int    `json:"rating"`
+		Text   *string `json:"text"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.Rating != nil {
+		rv.Rating = *req.Rating
+	}
+	if req.Text != nil {
+		rv.Text = *req.Text
+	}
+
+	if err := rv.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.reviewService.Update(r.Context(), rv); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newReviewResponse(rv), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleReviewDelete handles requests to delete a specified review of the authenticated user.
+func (s *Server) handleReviewDelete(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleReviewDelete")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	rv, err := s.opts.reviewService.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if rv.UserID != greenlight.UserIDFromContext(r.Context()) {
+		return greenlight.NewForbiddenError("You can only delete your own reviews.")
+	}
+
+	if err := s.opts.reviewService.Delete(r.Context(), id); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// reviewResponse represents a review sent to clients.
+type reviewResponse struct {
+	ID        int64     `json:"id"`
+	MovieID   int64     `json:"movie_id"`
+	UserID    int64     `json:"user_id"`
+	Rating    int       `json:"rating"`
+	Text      string    `json:"text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newReviewResponse(r *greenlight.Review) *reviewResponse {
+	return &reviewResponse{
+		ID:        r.ID,
+		MovieID:   r.MovieID,
+		UserID:    r.UserID,
+		Rating:    r.Rating,
+		Text:      r.Text,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
@@ -19,17 +19,23 @@ type Server struct {
 
 	opts options
 
+	// done is closed when the server is closed to end long-lived responses, e.g. event streams.
+	done chan struct{}
+
 	server *http.Server
 	router *http.ServeMux
 	logger *slog.Logger
 }
 
 // NewServer returns a new instance of [Server].
+// The services of the other features are set by the options, e.g. [WithBlobStore].
+// The routes of a feature are only registered if its service is set.
 func NewServer(addr string, movieService greenlight.MovieService, userService greenlight.UserService, authService *greenlight.AuthService, opts ...Option) *Server {
 	s := &Server{
 		movieService: movieService,
 		userService:  userService,
 		authService:  authService,
+		done:         make(chan struct{}),
 		server:       &http.Server{},
 		router:       http.NewServeMux(),
 		logger:       newLogger(),
@@ -45,6 +51,33 @@ func NewServer(addr string, movieService greenlight.MovieService, userService gr
 	s.registerMovieHandlers()
 	s.registerUserHandlers()
 	s.registerAuthHandlers()
+	s.registerStatsHandlers()
+	s.registerChangeHandlers()
+	if s.opts.blobStore != nil {
+		s.registerPosterHandlers()
+		s.registerBlobHandlers()
+	}
+	if s.opts.personService != nil {
+		s.registerPersonHandlers()
+	}
+	if s.opts.reviewService != nil {
+		s.registerReviewHandlers()
+	}
+	if s.opts.listService != nil {
+		s.registerListHandlers()
+	}
+	if s.opts.collectionService != nil {
+		s.registerCollectionHandlers()
+	}
+	if s.opts.genreService != nil {
+		s.registerGenreHandlers()
+	}
+	if s.opts.tagService != nil {
+		s.registerTagHandlers()
+	}
+	if s.opts.webhookService != nil {
+		s.registerWebhookHandlers()
+	}
 
 	return s
 }
@@ -81,6 +114,8 @@ func (s *Server) Close() (err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), s.opts.shutdownTimeout)
 	defer cancel()
 
+	// Shutdown waits for the active requests, so the event streams must be ended first.
+	close(s.done)
 	err = s.server.Shutdown(ctx)
 	if err != nil {
 		return err
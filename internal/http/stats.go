@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerStatsHandlers() {
+	s.router.Handle("GET /v1/stats/movies", s.authenticateOptional(s.handlerFunc(s.handleMovieStatsGet)))
+}
+
+// handleMovieStatsGet handles requests to get the statistics of the catalogue.
+// The statistics are refreshed periodically, so they may lag behind the catalogue.
+// Only the recent movies visible to the authenticated user are included, and the counts by status only for editors.
+func (s *Server) handleMovieStatsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieStatsGet")
+
+	viewer, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+
+	ctx := greenlight.NewContextWithLanguages(r.Context(), requestLanguages(r))
+	stats, err := s.movieService.Stats(ctx, viewer)
+	if err != nil {
+		return err
+	}
+	// The counts by status include the unpublished movies.
+	if !viewer.Role.Grants(greenlight.RoleEditor) {
+		stats.ByStatus = nil
+	}
+
+	recent, err := s.movieResponses(r.Context(), stats.Recent, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp := struct {
+		*greenlight.MovieStats
+		Recent []any `json:"recent"`
+	}{
+		MovieStats: stats,
+		Recent:     recent,
+	}
+
+	headers := make(http.Header)
+	headers.Set("Vary", "Accept-Language")
+	headers.Set("Last-Modified", stats.RefreshedAt.UTC().Format(http.TimeFormat))
+	if err := s.sendResponse(w, r, http.StatusOK, resp, headers); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerTagHandlers() {
+	s.router.Handle("GET /v1/tags", s.handlerFunc(s.handleTagsGet))
+	s.router.Handle("GET /v1/movies/{id}/tags", s.authenticateOptional(s.handlerFunc(s.handleMovieTagsGet)))
+	s.router.Handle("PUT /v1/movies/{id}/tags/{tag}", s.authenticate(s.handlerFunc(s.handleMovieTagAdd)))
+	s.router.Handle("DELETE /v1/movies/{id}/tags/{tag}", s.authenticate(s.handlerFunc(s.handleMovieTagRemove)))
+}
+
+// handleTagsGet handles requests to get the most popular tags.
+func (s *Server) handleTagsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleTagsGet")
+
+	filter := greenlight.TagFilter{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	vs := r.URL.Query()
+	filter.Prefix = greenlight.NormalizeTag(vs.Get("prefix"))
+	if err := queryInt(vs, "page", &filter.Page); err != nil {
+		return err
+	}
+	if err := queryInt(vs, "page_size", &filter.PageSize); err != nil {
+		return err
+	}
+
+	if err := filter.Valid(); err != nil {
+		return err
+	}
+
+	tags, err := s.opts.tagService.Popular(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, tags, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieTagsGet handles requests to get the tags of a specified movie.
+func (s *Server) handleMovieTagsGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieTagsGet")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError(`Invalid "ID" parameter format: %s`, idRaw)
+	}
+
+	if _, err := s.visibleMovie(r.Context(), id); err != nil {
+		return err
+	}
+	tags, err := s.opts.tagService.MovieTags(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, tags, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieTagAdd handles requests to add a tag to a specified movie, or to vote for the tag added by other users.
+func (s *Server) handleMovieTagAdd(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieTagAdd")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+	tag := greenlight.NormalizeTag(r.PathValue("tag"))
+	if err := greenlight.TagValid(tag); err != nil {
+		return err
+	}
+
+	if _, err := s.visibleMovie(r.Context(), id); err != nil {
+		return err
+	}
+	if err := s.opts.tagService.AddTag(r.Context(), id, tag); err != nil {
+		return err
+	}
+	tags, err := s.opts.tagService.MovieTags(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, tags, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleMovieTagRemove handles requests to remove the vote of the user for a tag of a specified movie.
+// Editors remove the tag altogether.
+func (s *Server) handleMovieTagRemove(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleMovieTagRemove")
+
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+	tag := greenlight.NormalizeTag(r.PathValue("tag"))
+
+	viewer, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+	if err := s.opts.tagService.RemoveTag(r.Context(), id, tag, viewer.Role.Grants(greenlight.RoleEditor)); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 
 func (s *Server) registerUserHandlers() {
 	s.router.Handle("POST /v1/users", s.handlerFunc(s.handleUserCreate))
+	s.router.Handle("GET /v1/users/me", s.authenticate(s.handlerFunc(s.handleProfileGet)))
+	s.router.Handle("PATCH /v1/users/me", s.authenticate(s.handlerFunc(s.handleProfileUpdate)))
 }
 
 // handleUserCreate handles requests to create (register) a user.
@@ -41,18 +43,76 @@ func (s *Server) handleUserCreate(w http.ResponseWriter, r *http.Request) (err e
 		return err
 	}
 
-	resp := struct {
-		ID    int64  `json:"id"`
-		Name  string `json:"name"`
-		Email string `json:"email"`
-	}{
-		ID:    u.ID,
-		Name:  u.Name,
-		Email: u.Email,
+	if err := s.sendResponse(w, r, http.StatusCreated, newUserResponse(u), nil); err != nil {
+		return err
 	}
+	return nil
+}
 
-	if err := s.sendResponse(w, r, http.StatusCreated, resp, nil); err != nil {
+// handleProfileGet handles requests to get the authenticated user.
+func (s *Server) handleProfileGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleProfileGet")
+
+	u, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newUserResponse(u), nil); err != nil {
 		return err
 	}
 	return nil
 }
+
+// handleProfileUpdate handles requests to update the settings of the authenticated user.
+func (s *Server) handleProfileUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleProfileUpdate")
+
+	u, err := s.viewer(r.Context())
+	if err != nil {
+		return err
+	}
+
+	// An empty maximum certification removes the limit.
+	var req struct {
+		MaxCertification *string `json:"max_certification"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.MaxCertification != nil {
+		u.MaxCertification = greenlight.Certification{}
+		if *req.MaxCertification != "" {
+			if u.MaxCertification, err = greenlight.ParseCertification(*req.MaxCertification); err != nil {
+				return err
+			}
+		}
+	}
+	if err := s.userService.Update(r.Context(), u); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newUserResponse(u), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// userResponse represents a user sent to clients.
+type userResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// MaxCertification is the maximum certification of the movies shown to the user in the "COUNTRY:RATING" format.
+	MaxCertification string `json:"max_certification,omitempty"`
+}
+
+func newUserResponse(u *greenlight.User) *userResponse {
+	return &userResponse{
+		ID:               u.ID,
+		Name:             u.Name,
+		Email:            u.Email,
+		MaxCertification: u.MaxCertification.String(),
+	}
+}
@@ -0,0 +1,282 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+func (s *Server) registerWebhookHandlers() {
+	// Webhooks deliver the changes of all the movies, including the unpublished ones, so they're only available to editors.
+	editor := func(h func(http.ResponseWriter, *http.Request) error) http.Handler {
+		return s.authenticate(s.requireRole(greenlight.RoleEditor, s.handlerFunc(h)))
+	}
+	s.router.Handle("GET /v1/users/me/webhooks", editor(s.handleWebhooksGet))
+	s.router.Handle("POST /v1/users/me/webhooks", editor(s.handleWebhookCreate))
+	s.router.Handle("GET /v1/users/me/webhooks/{id}", editor(s.handleWebhookGet))
+	s.router.Handle("PATCH /v1/users/me/webhooks/{id}", editor(s.handleWebhookUpdate))
+	s.router.Handle("DELETE /v1/users/me/webhooks/{id}", editor(s.handleWebhookDelete))
+	s.router.Handle("GET /v1/users/me/webhooks/{id}/deliveries", editor(s.handleWebhookDeliveriesGet))
+}
+
+// handleWebhooksGet handles requests to get all the webhooks of the authenticated user.
+func (s *Server) handleWebhooksGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleWebhooksGet")
+
+	webhooks, err := s.opts.webhookService.GetAll(r.Context(), greenlight.UserIDFromContext(r.Context()))
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*webhookResponse, len(webhooks))
+	for i, wh := range webhooks {
+		resp[i] = newWebhookResponse(wh)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleWebhookGet handles requests to get a specified webhook of the authenticated user.
+func (s *Server) handleWebhookGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleWebhookGet")
+
+	wh, err := s.myWebhook(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newWebhookResponse(wh), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleWebhookCreate handles requests to create a webhook of the authenticated user.
+func (s *Server) handleWebhookCreate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleWebhookCreate")
+
+	var req struct {
+		URL    string                     `json:"url"`
+		Events []greenlight.MovieChangeOp `json:"events"`
+		Secret string                     `json:"secret"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	wh := &greenlight.Webhook{
+		UserID: greenlight.UserIDFromContext(r.Context()),
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+		Active: true,
+	}
+	if err := wh.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.webhookService.Create(r.Context(), wh); err != nil {
+		return err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/users/me/webhooks/%d", wh.ID))
+	if err := s.sendResponse(w, r, http.StatusCreated, newWebhookResponse(wh), headers); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleWebhookUpdate handles requests to update a specified webhook of the authenticated user.
+// Activating a disabled webhook resets its failures.
+func (s *Server) handleWebhookUpdate(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleWebhookUpdate")
+
+	wh, err := s.myWebhook(r)
+	if err != nil {
+		return err
+	}
+
+	// use pointers to allow partial updates
+	var req struct {
+		URL    *string                     `json:"url"`
+		Events *[]greenlight.MovieChangeOp `json:"events"`
+		Secret *string                     `json:"secret"`
+		Active *bool                       `json:"active"`
+	}
+	if err := s.readRequest(w, r, &req); err != nil {
+		return err
+	}
+
+	if req.URL != nil {
+		wh.URL = *req.URL
+	}
+	if req.Events != nil {
+		wh.Events = *req.Events
+	}
+	if req.Secret != nil {
+		wh.Secret = *req.Secret
+	}
+	if req.Active != nil {
+		if *req.Active && !wh.Active {
+			wh.Failures = 0
+		}
+		wh.Active = *req.Active
+	}
+
+	if err := wh.Valid(); err != nil {
+		return err
+	}
+	if err := s.opts.webhookService.Update(r.Context(), wh); err != nil {
+		return err
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, newWebhookResponse(wh), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleWebhookDelete handles requests to delete a specified webhook of the authenticated user.
+func (s *Server) handleWebhookDelete(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleWebhookDelete")
+
+	wh, err := s.myWebhook(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.opts.webhookService.Delete(r.Context(), wh.ID); err != nil {
+		return err
+	}
+	if err := s.sendResponse(w, r, http.StatusNoContent, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleWebhookDeliveriesGet handles requests to get the delivery log of a specified webhook of the authenticated user.
+func (s *Server) handleWebhookDeliveriesGet(w http.ResponseWriter, r *http.Request) (err error) {
+	defer multierr.Wrap(&err, "http.Server.handleWebhookDeliveriesGet")
+
+	wh, err := s.myWebhook(r)
+	if err != nil {
+		return err
+	}
+
+	filter := greenlight.WebhookDeliveryFilter{
+		WebhookID: wh.ID,
+		Page:      1,
+		PageSize:  20,
+	}
+
+	vs := r.URL.Query()
+	filter.Status = greenlight.WebhookDeliveryStatus(vs.Get("status"))
+	if err := queryInt(vs, "page", &filter.Page); err != nil {
+		return err
+	}
+	if err := queryInt(vs, "page_size", &filter.PageSize); err != nil {
+		return err
+	}
+
+	if err := filter.Valid(); err != nil {
+		return err
+	}
+
+	deliveries, err := s.opts.webhookService.Deliveries(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]*webhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = newWebhookDeliveryResponse(d)
+	}
+
+	if err := s.sendResponse(w, r, http.StatusOK, resp, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// myWebhook returns the webhook of the "id" path parameter if it belongs to the authenticated user.
+func (s *Server) myWebhook(r *http.Request) (*greenlight.Webhook, error) {
+	idRaw := r.PathValue("id")
+	id, err := strconv.ParseInt(idRaw, 10, 64)
+	if err != nil || id < 0 {
+		return nil, greenlight.NewInvalidError("Invalid ID format: %s", idRaw)
+	}
+
+	wh, err := s.opts.webhookService.Get(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if wh.UserID != greenlight.UserIDFromContext(r.Context()) {
+		return nil, greenlight.ErrNotFound
+	}
+	return wh, nil
+}
+
+// webhookResponse represents a webhook sent to clients. The secret is never sent back.
+type webhookResponse struct {
+	ID        int64                      `json:"id"`
+	URL       string                     `json:"url"`
+	Events    []greenlight.MovieChangeOp `json:"events"`
+	Active    bool                       `json:"active"`
+	Failures  int                        `json:"failures"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+func newWebhookResponse(wh *greenlight.Webhook) *webhookResponse {
+	events := wh.Events
+	if events == nil {
+		events = []greenlight.MovieChangeOp{}
+	}
+	return &webhookResponse{
+		ID:        wh.ID,
+		URL:       wh.URL,
+		Events:    events,
+		Active:    wh.Active,
+		Failures:  wh.Failures,
+		CreatedAt: wh.CreatedAt,
+	}
+}
+
+// webhookDeliveryResponse represents a webhook delivery sent to clients.
+type webhookDeliveryResponse struct {
+	ID            int64                            `json:"id"`
+	Change        *greenlight.MovieChange          `json:"change"`
+	Status        greenlight.WebhookDeliveryStatus `json:"status"`
+	Attempts      int                              `json:"attempts"`
+	StatusCode    int                              `json:"status_code,omitempty"`
+	Error         string                           `json:"error,omitempty"`
+	LastAttemptAt *time.Time                       `json:"last_attempt_at,omitempty"`
+	// NextAttemptAt is only sent for the pending deliveries.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func newWebhookDeliveryResponse(d *greenlight.WebhookDelivery) *webhookDeliveryResponse {
+	resp := &webhookDeliveryResponse{
+		ID:         d.ID,
+		Change:     d.Change,
+		Status:     d.Status,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		CreatedAt:  d.CreatedAt,
+	}
+	if !d.LastAttemptAt.IsZero() {
+		resp.LastAttemptAt = &d.LastAttemptAt
+	}
+	if d.Status == greenlight.DeliveryPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	return resp
+}
@@ -0,0 +1,290 @@
+// Package jsonpatch implements JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrTestFailed is returned when a "test" operation of a JSON Patch fails.
+var ErrTestFailed = errors.New("test operation failed")
+
+// MergePatch applies the JSON Merge Patch to the JSON document and returns the patched document.
+// Members of the patch set to null remove the members of the document, objects are merged recursively
+// and any other value, including an array, replaces the value of the document.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var d, p any
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	return json.Marshal(mergePatch(d, p))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// Operation is an operation of a JSON Patch.
+type Operation struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	From string `json:"from"`
+	// Value is nil if the value is absent, as opposed to a JSON null.
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply applies the JSON Patch to the JSON document and returns the patched document.
+// Operations are applied in order, and the patch is rejected as a whole if any of them fails.
+// A failed "test" operation is reported with [ErrTestFailed].
+func Apply(doc, patch []byte) ([]byte, error) {
+	var d any
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+
+	for i, op := range ops {
+		var err error
+		if d, err = apply(d, op); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return json.Marshal(d)
+}
+
+// apply applies the operation to the document and returns the patched document.
+func apply(doc any, op Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%q operation requires a value", op.Op)
+		}
+		var v any
+		if err := json.Unmarshal(op.Value, &v); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, v)
+		case "replace":
+			if doc, err = remove(doc, path); err != nil {
+				return nil, err
+			}
+			return add(doc, path, v)
+		default:
+			cur, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(cur, v) {
+				return nil, fmt.Errorf("%w: %q", ErrTestFailed, op.Path)
+			}
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return add(doc, path, clone(v))
+		}
+		if len(path) > len(from) && slices.Equal(path[:len(from)], from) {
+			return nil, fmt.Errorf("cannot move %q into its child %q", op.From, op.Path)
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// parsePointer parses the JSON Pointer (RFC 6901) to its reference tokens.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("invalid path %q", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// get returns the value referenced by the path.
+func get(doc any, path []string) (any, error) {
+	for _, t := range path {
+		switch n := doc.(type) {
+		case map[string]any:
+			v, ok := n[t]
+			if !ok {
+				return nil, fmt.Errorf("path member %q not found", t)
+			}
+			doc = v
+		case []any:
+			i, err := index(t, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = n[i]
+		default:
+			return nil, fmt.Errorf("path member %q not found", t)
+		}
+	}
+	return doc, nil
+}
+
+// add adds the value at the path, inserting it into arrays, and returns the patched document.
+func add(doc any, path []string, v any) (any, error) {
+	return update(doc, path, func(parent any, t string) (any, error) {
+		switch n := parent.(type) {
+		case map[string]any:
+			n[t] = v
+			return n, nil
+		case []any:
+			if t == "-" {
+				return append(n, v), nil
+			}
+			i, err := index(t, len(n))
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(n, i, v), nil
+		default:
+			return nil, fmt.Errorf("path member %q not found", t)
+		}
+	}, v)
+}
+
+// remove removes the value at the path and returns the patched document.
+func remove(doc any, path []string) (any, error) {
+	return update(doc, path, func(parent any, t string) (any, error) {
+		switch n := parent.(type) {
+		case map[string]any:
+			if _, ok := n[t]; !ok {
+				return nil, fmt.Errorf("path member %q not found", t)
+			}
+			delete(n, t)
+			return n, nil
+		case []any:
+			i, err := index(t, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(n, i, i+1), nil
+		default:
+			return nil, fmt.Errorf("path member %q not found", t)
+		}
+	}, nil)
+}
+
+// update calls fn with the parent of the value referenced by the path and the last path token,
+// replacing the parent with the returned value. The empty path replaces the whole document with the root value.
+func update(doc any, path []string, fn func(parent any, t string) (any, error), root any) (any, error) {
+	if len(path) == 0 {
+		return root, nil
+	}
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	t := path[0]
+	switch n := doc.(type) {
+	case map[string]any:
+		child, ok := n[t]
+		if !ok {
+			return nil, fmt.Errorf("path member %q not found", t)
+		}
+		v, err := update(child, path[1:], fn, root)
+		if err != nil {
+			return nil, err
+		}
+		n[t] = v
+		return n, nil
+	case []any:
+		i, err := index(t, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := update(n[i], path[1:], fn, root)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = v
+		return n, nil
+	default:
+		return nil, fmt.Errorf("path member %q not found", t)
+	}
+}
+
+// index parses the array index token, which must not be greater than last.
+func index(t string, last int) (int, error) {
+	valid := t != "" && strings.Trim(t, "0123456789") == "" && (t == "0" || t[0] != '0')
+	i, err := strconv.Atoi(t)
+	if !valid || err != nil || i > last {
+		return 0, fmt.Errorf("invalid array index %q", t)
+	}
+	return i, nil
+}
+
+// clone returns a deep copy of the decoded JSON value.
+func clone(v any) any {
+	switch n := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(n))
+		for k, v := range n {
+			c[k] = clone(v)
+		}
+		return c
+	case []any:
+		c := make([]any, len(n))
+		for i, v := range n {
+			c[i] = clone(v)
+		}
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,2 @@
+// Package movieio implements reading and writing movies in bulk data formats.
+package movieio
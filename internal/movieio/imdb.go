@@ -0,0 +1,225 @@
+package movieio
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+// imdbColumns are the columns of the IMDb "title.basics.tsv.gz" dataset used by the [IMDbReader].
+var imdbColumns = []string{"tconst", "titleType", "primaryTitle", "startYear", "runtimeMinutes", "genres"}
+
+// imdbNull is the IMDb dataset representation of a missing value.
+const imdbNull = `\N`
+
+// IMDbReader reads movies from the gzipped IMDb "title.basics.tsv.gz" dataset.
+// Titles other than movies are skipped.
+type IMDbReader struct {
+	sc   *bufio.Scanner
+	cols map[string]int
+	line int
+	// skipped is the number of the skipped titles.
+	skipped int
+}
+
+// NewIMDbReader returns a new [IMDbReader] reading the gzipped dataset from r.
+// The header row is read immediately.
+func NewIMDbReader(r io.Reader) (*IMDbReader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, greenlight.NewInvalidError("IMDb dataset must be gzipped: %v", err)
+	}
+	sc := bufio.NewScanner(zr)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, greenlight.NewInvalidError("IMDb dataset must start with a header row.")
+	}
+	cols := make(map[string]int)
+	for i, h := range strings.Split(sc.Text(), "\t") {
+		cols[h] = i
+	}
+	for _, c := range imdbColumns {
+		if _, ok := cols[c]; !ok {
+			return nil, greenlight.NewInvalidError("IMDb dataset header row must contain a %q column.", c)
+		}
+	}
+	return &IMDbReader{sc: sc, cols: cols, line: 1}, nil
+}
+
+// Read returns the next movie and its line number. It returns [io.EOF] at the end of the input.
+// Genres are the IMDb genre names, e.g. "Sci-Fi". Release dates are the first days of the release years.
+// Malformed rows are reported by a [greenlight.InvalidError], and reading can continue with the next row.
+func (r *IMDbReader) Read() (m *greenlight.Movie, line int, err error) {
+	for {
+		if !r.sc.Scan() {
+			if err := r.sc.Err(); err != nil {
+				return nil, r.line + 1, err
+			}
+			return nil, r.line, io.EOF
+		}
+		r.line++
+
+		// Fields aren't quoted in the IMDb datasets, so titles may contain quotes.
+		rec := strings.Split(r.sc.Text(), "\t")
+		if len(rec) != len(r.cols) {
+			return nil, r.line, greenlight.NewInvalidError("Malformed IMDb row: want %d fields, got %d.", len(r.cols), len(rec))
+		}
+		field := func(name string) string {
+			if v := rec[r.cols[name]]; v != imdbNull {
+				return v
+			}
+			return ""
+		}
+
+		if field("titleType") != "movie" {
+			r.skipped++
+			continue
+		}
+
+		ierr := greenlight.NewInvalidError("Movie is invalid.")
+		m := &greenlight.Movie{Title: field("primaryTitle"), ExternalIDs: map[string]string{"imdb": field("tconst")}}
+		if v := field("startYear"); v != "" {
+			y, err := strconv.Atoi(v)
+			if err != nil {
+				ierr.AddViolationMsg("release_date", fmt.Sprintf("Invalid year format: %s", v))
+			}
+			m.ReleaseDate = time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		}
+		if v := field("runtimeMinutes"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				ierr.AddViolationMsg("runtime", fmt.Sprintf("Invalid number format: %s", v))
+			}
+			m.Runtime = n
+		}
+		if v := field("genres"); v != "" {
+			m.Genres = strings.Split(v, ",")
+		}
+
+		if len(ierr.Violations()) != 0 {
+			return nil, r.line, ierr
+		}
+		return m, r.line, nil
+	}
+}
+
+// Skipped returns the number of the titles skipped so far for not being movies.
+func (r *IMDbReader) Skipped() int {
+	return r.skipped
+}
+
+// IMDbStats are statistics of an IMDb dataset import.
+type IMDbStats struct {
+	// Lines is the number of the read data lines.
+	Lines int `json:"lines"`
+	// Skipped is the number of the titles skipped for not being movies.
+	Skipped int `json:"skipped"`
+	// Invalid is the number of the movies skipped for being invalid.
+	Invalid int `json:"invalid"`
+	greenlight.UpsertStats
+	// UnknownGenres are counts of the IMDb genres missing from the genre vocabulary, keyed by the genre name.
+	// The genres are dropped from the movies.
+	UnknownGenres map[string]int `json:"unknown_genres"`
+}
+
+// nonSlugRx matches runs of characters that aren't allowed in genre slugs.
+var nonSlugRx = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ImportIMDb reads movies from the gzipped IMDb "title.basics.tsv.gz" dataset and upserts the valid ones
+// by their IMDb IDs in batches, so re-running the import only changes the movies that changed.
+// IMDb genres are matched with the genre vocabulary by the slug or the name.
+// If progress is not nil, it is called with the statistics so far after each batch.
+func (im *Importer) ImportIMDb(ctx context.Context, r io.Reader, progress func(IMDbStats)) (*IMDbStats, error) {
+	genres, err := im.genreService.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("movieio.Importer.ImportIMDb: %w", err)
+	}
+	known := make(map[string]string, 2*len(genres))
+	for _, g := range genres {
+		known[g.Slug] = g.Slug
+		known[strings.ToLower(g.Name)] = g.Slug
+	}
+
+	rd, err := NewIMDbReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &IMDbStats{UnknownGenres: make(map[string]int)}
+	batch := make([]*greenlight.Movie, 0, im.batchSize)
+	flush := func() error {
+		if len(batch) != 0 {
+			us, err := im.movieService.Upsert(ctx, "imdb", batch)
+			if err != nil {
+				return fmt.Errorf("movieio.Importer.ImportIMDb: %w", err)
+			}
+			stats.Inserted += us.Inserted
+			stats.Updated += us.Updated
+			stats.Unchanged += us.Unchanged
+			stats.Duplicates += us.Duplicates
+			batch = batch[:0]
+		}
+		stats.Lines, stats.Skipped = rd.line-1, rd.Skipped()
+		if progress != nil {
+			progress(*stats)
+		}
+		return nil
+	}
+
+	for {
+		m, line, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			slugs := make([]string, 0, len(m.Genres))
+			for _, g := range m.Genres {
+				slug, ok := known[strings.Trim(nonSlugRx.ReplaceAllString(strings.ToLower(g), "-"), "-")]
+				if !ok {
+					slug, ok = known[strings.ToLower(g)]
+				}
+				if !ok {
+					stats.UnknownGenres[g]++
+					continue
+				}
+				if !slices.Contains(slugs, slug) {
+					slugs = append(slugs, slug)
+				}
+			}
+			m.Genres = slugs
+			err = m.Valid()
+		}
+		if err != nil {
+			if !errors.As(err, new(*greenlight.InvalidError)) {
+				return stats, fmt.Errorf("movieio.Importer.ImportIMDb: line %d: %w", line, err)
+			}
+			stats.Invalid++
+			continue
+		}
+
+		batch = append(batch, m)
+		if len(batch) == im.batchSize {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
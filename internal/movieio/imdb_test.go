@@ -0,0 +1,74 @@
+package movieio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+func TestIMDbReader(t *testing.T) {
+	const tsv = "tconst\ttitleType\tprimaryTitle\toriginalTitle\tisAdult\tstartYear\tendYear\truntimeMinutes\tgenres\n" +
+		"tt0000001\tshort\tCarmencita\tCarmencita\t0\t1894\t\\N\t1\tDocumentary,Short\n" +
+		"tt0111161\tmovie\tThe Shawshank Redemption\tThe Shawshank Redemption\t0\t1994\t\\N\t142\tDrama\n" +
+		"tt0133093\tmovie\tThe Matrix\tThe Matrix\t0\t1999\t\\N\t136\tAction,Sci-Fi\n" +
+		"tt0903747\ttvSeries\tBreaking Bad\tBreaking Bad\t0\t2008\t2013\t49\tCrime,Drama,Thriller\n" +
+		"tt9999999\tmovie\tUntitled\tUntitled\t0\tsoon\t\\N\t\\N\t\\N\n"
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(tsv)); err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+
+	r, err := NewIMDbReader(&buf)
+	if err != nil {
+		t.Fatalf("new reader: %v", err)
+	}
+
+	want := []*greenlight.Movie{
+		{ExternalIDs: map[string]string{"imdb": "tt0111161"}, Title: "The Shawshank Redemption", ReleaseDate: time.Date(1994, 1, 1, 0, 0, 0, 0, time.UTC), Runtime: 142, Genres: []string{"Drama"}},
+		{ExternalIDs: map[string]string{"imdb": "tt0133093"}, Title: "The Matrix", ReleaseDate: time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), Runtime: 136, Genres: []string{"Action", "Sci-Fi"}},
+	}
+	var got []*greenlight.Movie
+	var invalid []int
+	for {
+		m, line, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		switch {
+		case errors.As(err, new(*greenlight.InvalidError)):
+			invalid = append(invalid, line)
+		case err != nil:
+			t.Fatalf("read: %v", err)
+		default:
+			got = append(got, m)
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("want movies: %d, got: %d", len(want), len(got))
+	}
+	for i, m := range got {
+		w := want[i]
+		if !maps.Equal(m.ExternalIDs, w.ExternalIDs) || m.Title != w.Title || !m.ReleaseDate.Equal(w.ReleaseDate) || m.Runtime != w.Runtime || !slices.Equal(m.Genres, w.Genres) {
+			t.Errorf("want movie: %+v, got: %+v", w, m)
+		}
+	}
+	if !slices.Equal(invalid, []int{6}) {
+		t.Errorf("want invalid lines: %v, got: %v", []int{6}, invalid)
+	}
+	if r.Skipped() != 2 {
+		t.Errorf("want skipped: %d, got: %d", 2, r.Skipped())
+	}
+}
@@ -0,0 +1,150 @@
+package movieio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+// maxFailures is the maximum number of failures listed in a [Report].
+const maxFailures = 1000
+
+// Report is a report of an import.
+type Report struct {
+	// Imported is the number of the imported movies.
+	Imported int `json:"imported"`
+	// Failed is the number of the rows that failed to import.
+	Failed int `json:"failed"`
+	// Failures are the first of the rows that failed to import, in the input order.
+	Failures []Failure `json:"failures"`
+}
+
+// Failure is a failure to import a row.
+type Failure struct {
+	// Line is the line number the row starts at.
+	Line int `json:"line"`
+	// Msg is a description of the failure.
+	Msg string `json:"error"`
+	// Fields are descriptions of the failure keyed by the invalid field names.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Importer imports movies in bulk.
+type Importer struct {
+	movieService greenlight.MovieService
+	genreService greenlight.GenreService
+	batchSize    int
+}
+
+// NewImporter returns a new instance of [Importer].
+func NewImporter(movieService greenlight.MovieService, genreService greenlight.GenreService) *Importer {
+	return &Importer{
+		movieService: movieService,
+		genreService: genreService,
+		batchSize:    1000,
+	}
+}
+
+// Import reads movies of the format from r and creates the valid ones in batches with the status.
+// Invalid rows are skipped and listed in the report. On error, the report covers the rows read so far.
+func (im *Importer) Import(ctx context.Context, r io.Reader, format Format, status greenlight.MovieStatus) (*Report, error) {
+	genres, err := im.genreService.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("movieio.Importer.Import: %w", err)
+	}
+	known := make(map[string]bool, len(genres))
+	for _, g := range genres {
+		known[g.Slug] = true
+	}
+
+	rd, err := NewReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Failures: []Failure{}}
+	batch := make([]*greenlight.Movie, 0, im.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := im.movieService.Import(ctx, batch); err != nil {
+			return fmt.Errorf("movieio.Importer.Import: %w", err)
+		}
+		report.Imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		m, line, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			err = validate(m, known)
+		}
+		if err != nil {
+			var ierr *greenlight.InvalidError
+			if !errors.As(err, &ierr) {
+				return report, fmt.Errorf("movieio.Importer.Import: line %d: %w", line, err)
+			}
+			report.fail(line, ierr)
+			continue
+		}
+
+		m.Status = status
+		batch = append(batch, m)
+		if len(batch) == im.batchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// validate returns an error if the movie is invalid or has genres missing from the known ones.
+func validate(m *greenlight.Movie, known map[string]bool) error {
+	if err := m.Valid(); err != nil {
+		return err
+	}
+
+	var unknown []string
+	for _, g := range m.Genres {
+		if !known[g] && !slices.Contains(unknown, g) {
+			unknown = append(unknown, g)
+		}
+	}
+	if len(unknown) != 0 {
+		err := greenlight.NewInvalidError("Movie is invalid.")
+		err.AddViolationMsg("genres", fmt.Sprintf("Unknown genre(s): %s.", strings.Join(unknown, ", ")))
+		return err
+	}
+	return nil
+}
+
+// fail records the failure of the row at the line.
+func (r *Report) fail(line int, err *greenlight.InvalidError) {
+	r.Failed++
+	if len(r.Failures) == maxFailures {
+		return
+	}
+
+	f := Failure{Line: line, Msg: err.Msg}
+	if vs := err.Violations(); len(vs) != 0 {
+		f.Fields = make(map[string]string, len(vs))
+		for k, v := range vs {
+			f.Fields[k] = v.Error()
+		}
+	}
+	r.Failures = append(r.Failures, f)
+}
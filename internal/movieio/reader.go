@@ -0,0 +1,193 @@
+package movieio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+// Format is a bulk data format of movies.
+type Format string
+
+const (
+	// CSV is a comma-separated values format with a header row naming the columns:
+	// "title", "release_date" (YYYY-MM-DD), "runtime" and "genres" (separated by "|").
+	CSV Format = "csv"
+	// NDJSON is a newline-delimited JSON format with a movie object per line.
+	NDJSON Format = "ndjson"
+	// JSON is a JSON array of movie objects. It is only supported for writing.
+	JSON Format = "json"
+)
+
+// ParseFormat returns the format named by a format name, a file extension or a media type.
+func ParseFormat(s string) (Format, error) {
+	s, _, _ = strings.Cut(strings.ToLower(strings.TrimPrefix(s, ".")), ";")
+	switch strings.TrimSpace(s) {
+	case "csv", "text/csv":
+		return CSV, nil
+	case "ndjson", "jsonl", "application/x-ndjson", "application/jsonl":
+		return NDJSON, nil
+	case "json", "application/json":
+		return JSON, nil
+	default:
+		return "", greenlight.NewInvalidError("Unsupported format %q: must be CSV, NDJSON or JSON.", s)
+	}
+}
+
+// maxLineSize is the maximum size of an NDJSON line.
+const maxLineSize = 1 << 20
+
+// Reader reads movies from CSV or NDJSON input.
+type Reader struct {
+	csv    *csv.Reader
+	cols   map[string]int
+	ndjson *bufio.Scanner
+	line   int
+}
+
+// NewReader returns a new [Reader] reading movies of the format from r.
+// For the CSV format, the header row is read immediately.
+func NewReader(r io.Reader, format Format) (*Reader, error) {
+	switch format {
+	case CSV:
+		cr := csv.NewReader(r)
+		cr.ReuseRecord = true
+		cr.TrimLeadingSpace = true
+
+		header, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, greenlight.NewInvalidError("CSV input must start with a header row.")
+			}
+			return nil, greenlight.NewInvalidError("Invalid CSV header row: %v", err)
+		}
+		cols := make(map[string]int, len(header))
+		for i, h := range header {
+			cols[strings.ToLower(strings.TrimSpace(h))] = i
+		}
+		for _, c := range []string{"title", "release_date", "runtime", "genres"} {
+			if _, ok := cols[c]; !ok {
+				return nil, greenlight.NewInvalidError("CSV header row must contain a %q column.", c)
+			}
+		}
+		return &Reader{csv: cr, cols: cols}, nil
+	case NDJSON:
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &Reader{ndjson: sc}, nil
+	case JSON:
+		return nil, greenlight.NewInvalidError("JSON format can't be imported, use NDJSON instead.")
+	default:
+		return nil, fmt.Errorf("movieio: unknown format %q", format)
+	}
+}
+
+// Read returns the next movie and the line number it starts at. It returns [io.EOF] at the end of the input.
+// Malformed rows are reported by a [greenlight.InvalidError], and reading can continue with the next row.
+// Movies are not validated, see [greenlight.Movie.Valid].
+func (r *Reader) Read() (m *greenlight.Movie, line int, err error) {
+	if r.csv != nil {
+		return r.readCSV()
+	}
+	return r.readNDJSON()
+}
+
+func (r *Reader) readCSV() (*greenlight.Movie, int, error) {
+	rec, err := r.csv.Read()
+	if err != nil {
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			return nil, perr.StartLine, greenlight.NewInvalidError("Malformed CSV row: %v", perr.Err)
+		}
+		return nil, 0, err
+	}
+	line, _ := r.csv.FieldPos(0)
+
+	field := func(name string) string {
+		if i := r.cols[name]; i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	ierr := greenlight.NewInvalidError("Movie is invalid.")
+
+	m := &greenlight.Movie{Title: field("title")}
+	if v := field("release_date"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			ierr.AddViolationMsg("release_date", fmt.Sprintf("Invalid date format: %s", v))
+		}
+		m.ReleaseDate = t
+	}
+	if v := field("runtime"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			ierr.AddViolationMsg("runtime", fmt.Sprintf("Invalid number format: %s", v))
+		}
+		m.Runtime = n
+	}
+	if v := field("genres"); v != "" {
+		for _, g := range strings.Split(v, "|") {
+			m.Genres = append(m.Genres, strings.TrimSpace(g))
+		}
+	}
+
+	if len(ierr.Violations()) != 0 {
+		return nil, line, ierr
+	}
+	return m, line, nil
+}
+
+func (r *Reader) readNDJSON() (*greenlight.Movie, int, error) {
+	var b []byte
+	for len(b) == 0 {
+		if !r.ndjson.Scan() {
+			if err := r.ndjson.Err(); err != nil {
+				if errors.Is(err, bufio.ErrTooLong) {
+					return nil, r.line + 1, fmt.Errorf("line %d is longer than %d bytes", r.line+1, maxLineSize)
+				}
+				return nil, r.line, err
+			}
+			return nil, r.line, io.EOF
+		}
+		r.line++
+		b = bytes.TrimSpace(r.ndjson.Bytes())
+	}
+
+	// Fields written by the [Writer] that can't be imported are accepted and ignored.
+	var row struct {
+		ID          int64    `json:"id"`
+		Title       string   `json:"title"`
+		ReleaseDate string   `json:"release_date"`
+		Runtime     int      `json:"runtime"`
+		Genres      []string `json:"genres"`
+		Rating      float64  `json:"rating"`
+		Votes       int      `json:"votes"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&row); err != nil {
+		return nil, r.line, greenlight.NewInvalidError("Malformed JSON: %v", err)
+	}
+
+	m := &greenlight.Movie{Title: row.Title, Runtime: row.Runtime, Genres: row.Genres}
+	if row.ReleaseDate != "" {
+		t, err := time.Parse(time.DateOnly, row.ReleaseDate)
+		if err != nil {
+			ierr := greenlight.NewInvalidError("Movie is invalid.")
+			ierr.AddViolationMsg("release_date", fmt.Sprintf("Invalid date format: %s", row.ReleaseDate))
+			return nil, r.line, ierr
+		}
+		m.ReleaseDate = t
+	}
+	return m, r.line, nil
+}
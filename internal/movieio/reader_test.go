@@ -0,0 +1,84 @@
+package movieio
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		// want are the titles of the read movies, or "!" for a malformed row, with the line numbers.
+		want      []string
+		wantLines []int
+	}{
+		{
+			name:   "csv",
+			format: CSV,
+			input: "title,release_date,runtime,genres\n" +
+				"Moana,2016-11-14,107,animation|adventure\n" +
+				"\"Black\nPanther\",2018-01-29,134,action\n" +
+				"Deadpool,2016-02-18,abc,action\n" +
+				"The Breakfast Club,1985-02-15,96\n",
+			want:      []string{"Moana", "Black\nPanther", "!", "!"},
+			wantLines: []int{2, 3, 5, 6},
+		},
+		{
+			name:   "ndjson",
+			format: NDJSON,
+			input: `{"title":"Moana","release_date":"2016-11-14","runtime":107,"genres":["animation"]}` + "\n" +
+				"\n" +
+				`{"title":"Deadpool","release_date":"2016/02/18","runtime":108,"genres":["action"]}` + "\n" +
+				`{"title":"Black Panther","year":2018}` + "\n" +
+				`{"title":"The Breakfast Club","release_date":"1985-02-15","runtime":96,"genres":["drama"]}`,
+			want:      []string{"Moana", "!", "!", "The Breakfast Club"},
+			wantLines: []int{1, 3, 4, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("new reader: %v", err)
+			}
+
+			var got []string
+			var gotLines []int
+			for {
+				m, line, err := r.Read()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				switch {
+				case errors.As(err, new(*greenlight.InvalidError)):
+					got = append(got, "!")
+				case err != nil:
+					t.Fatalf("read: %v", err)
+				default:
+					got = append(got, m.Title)
+				}
+				gotLines = append(gotLines, line)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("want movies: %q, got: %q", tt.want, got)
+			}
+			if !slices.Equal(gotLines, tt.wantLines) {
+				t.Errorf("want lines: %v, got: %v", tt.wantLines, gotLines)
+			}
+		})
+	}
+}
+
+func TestReaderCSVHeader(t *testing.T) {
+	_, err := NewReader(strings.NewReader("title,runtime,genres\n"), CSV)
+	if !errors.As(err, new(*greenlight.InvalidError)) {
+		t.Errorf("want invalid error, got: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package movieio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// row represents a movie written as a JSON object.
+type row struct {
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	ReleaseDate string   `json:"release_date"`
+	Runtime     int      `json:"runtime"`
+	Genres      []string `json:"genres"`
+	Rating      float64  `json:"rating"`
+	Votes       int      `json:"votes"`
+}
+
+// csvHeader is the header row of the written CSV. Columns read by the [Reader] have the same names.
+var csvHeader = []string{"id", "title", "release_date", "runtime", "genres", "rating", "votes"}
+
+// Writer writes movies in CSV, NDJSON or JSON format. Writes are buffered, [Writer.Close] must be called to flush them.
+type Writer struct {
+	w      *bufio.Writer
+	csv    *csv.Writer
+	format Format
+	n      int
+}
+
+// NewWriter returns a new [Writer] writing movies of the format to w.
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	wr := &Writer{w: bufio.NewWriter(w), format: format}
+	switch format {
+	case CSV:
+		wr.csv = csv.NewWriter(wr.w)
+		if err := wr.csv.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	case NDJSON:
+	case JSON:
+		if err := wr.w.WriteByte('['); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("movieio: unknown format %q", format)
+	}
+	return wr, nil
+}
+
+// Write writes the movie.
+func (w *Writer) Write(m *greenlight.Movie) error {
+	defer func() { w.n++ }()
+
+	if w.format == CSV {
+		return w.csv.Write([]string{
+			strconv.FormatInt(m.ID, 10),
+			m.Title,
+			m.ReleaseDate.Format(time.DateOnly),
+			strconv.Itoa(m.Runtime),
+			strings.Join(m.Genres, "|"),
+			strconv.FormatFloat(m.Rating, 'f', -1, 64),
+			strconv.Itoa(m.Votes),
+		})
+	}
+
+	js, err := json.Marshal(row{
+		ID:          m.ID,
+		Title:       m.Title,
+		ReleaseDate: m.ReleaseDate.Format(time.DateOnly),
+		Runtime:     m.Runtime,
+		Genres:      m.Genres,
+		Rating:      m.Rating,
+		Votes:       m.Votes,
+	})
+	if err != nil {
+		return err
+	}
+	switch {
+	case w.format == NDJSON:
+		js = append(js, '\n')
+	case w.n == 0:
+		js = append([]byte("\n"), js...)
+	default:
+		js = append([]byte(",\n"), js...)
+	}
+	_, err = w.w.Write(js)
+	return err
+}
+
+// Flush writes the buffered movies to the underlying writer.
+func (w *Writer) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
+
+// Close finishes the output and flushes it. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.format == JSON {
+		if _, err := w.w.WriteString("\n]\n"); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,85 @@
+package movieio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	movies := []*greenlight.Movie{
+		{ID: 1, Title: "Moana", ReleaseDate: time.Date(2016, 11, 14, 0, 0, 0, 0, time.UTC), Runtime: 107, Genres: []string{"animation", "adventure"}},
+		{ID: 2, Title: "Black, \"Panther\"", ReleaseDate: time.Date(2018, 1, 29, 0, 0, 0, 0, time.UTC), Runtime: 134, Genres: []string{"action"}, Rating: 4.5, Votes: 2},
+	}
+
+	for _, format := range []Format{CSV, NDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatalf("new writer: %v", err)
+			}
+			for _, m := range movies {
+				if err := w.Write(m); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			r, err := NewReader(&buf, format)
+			if err != nil {
+				t.Fatalf("new reader: %v", err)
+			}
+			for i := 0; ; i++ {
+				m, _, err := r.Read()
+				if errors.Is(err, io.EOF) {
+					if i != len(movies) {
+						t.Fatalf("want movies: %d, got: %d", len(movies), i)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatalf("read: %v", err)
+				}
+				want := movies[i]
+				if m.Title != want.Title || !m.ReleaseDate.Equal(want.ReleaseDate) || m.Runtime != want.Runtime || !slices.Equal(m.Genres, want.Genres) {
+					t.Errorf("want movie: %+v, got: %+v", want, m)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterJSON(t *testing.T) {
+	for _, n := range []int{0, 2} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, JSON)
+		if err != nil {
+			t.Fatalf("new writer: %v", err)
+		}
+		for i := range n {
+			if err := w.Write(&greenlight.Movie{ID: int64(i + 1), Title: "Moana"}); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+
+		var got []map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal %q: %v", buf.String(), err)
+		}
+		if len(got) != n {
+			t.Errorf("want movies: %d, got: %d", n, len(got))
+		}
+	}
+}
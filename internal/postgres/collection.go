@@ -0,0 +1,310 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+	"github.com/lib/pq"
+)
+
+// CollectionService represents a service for managing collections backed by PostgreSQL.
+type CollectionService struct {
+	db *DB
+}
+
+var _ greenlight.CollectionService = (*CollectionService)(nil)
+
+// NewCollectionService returns a new instance of [CollectionService].
+func NewCollectionService(db *DB) *CollectionService {
+	return &CollectionService{
+		db: db,
+	}
+}
+
+// collectionColumns is the select list of the collection columns scanned by [scanCollection].
+const collectionColumns = `
+	c.id, c.slug, c.title, c.description, c.publish_from, c.publish_until, c.version,
+	ARRAY(SELECT movie_id FROM collection_movies cm WHERE cm.collection_id = c.id ORDER BY cm.position)`
+
+// scanCollection scans a row selected using the [collectionColumns].
+func scanCollection(row interface{ Scan(...any) error }) (*greenlight.Collection, error) {
+	var c greenlight.Collection
+	var from, until sql.NullTime
+	if err := row.Scan(&c.ID, &c.Slug, &c.Title, &c.Description, &from, &until, &c.Version, pq.Array(&c.MovieIDs)); err != nil {
+		return nil, err
+	}
+	c.PublishFrom, c.PublishUntil = from.Time, until.Time
+	return &c, nil
+}
+
+func (s *CollectionService) Get(ctx context.Context, id int64) (_ *greenlight.Collection, err error) {
+	defer multierr.Wrap(&err, "postgres.CollectionService.Get(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`SELECT %s FROM collections c WHERE c.id = $1`, collectionColumns)
+	c, err := scanCollection(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *CollectionService) GetBySlug(ctx context.Context, slug string) (_ *greenlight.Collection, err error) {
+	defer multierr.Wrap(&err, "postgres.CollectionService.GetBySlug(%q)", slug)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`SELECT %s FROM collections c WHERE c.slug = $1`, collectionColumns)
+	c, err := scanCollection(tx.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *CollectionService) GetAll(ctx context.Context, published bool) (_ []*greenlight.Collection, err error) {
+	defer multierr.Wrap(&err, "postgres.CollectionService.GetAll")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM collections c
+		WHERE NOT $1 OR (c.publish_from <= NOW() AND (c.publish_until IS NULL OR c.publish_until > NOW()))
+		ORDER BY c.publish_from DESC NULLS LAST, c.id ASC`, collectionColumns)
+	rs, err := tx.QueryContext(ctx, query, published)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var collections []*greenlight.Collection
+	for rs.Next() {
+		c, err := scanCollection(rs)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+func (s *CollectionService) Create(ctx context.Context, c *greenlight.Collection) (err error) {
+	defer multierr.Wrap(&err, "postgres.CollectionService.Create")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO collections (slug, title, description, publish_from, publish_until) VALUES ($1, $2, $3, $4, $5) RETURNING id, version`
+	args := []any{c.Slug, c.Title, c.Description, nullTime(c.PublishFrom), nullTime(c.PublishUntil)}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&c.ID, &c.Version); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("A collection with this slug already exists.")
+		default:
+			return err
+		}
+	}
+	if err := setCollectionMovies(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CollectionService) Update(ctx context.Context, c *greenlight.Collection) (err error) {
+	defer multierr.Wrap(&err, "postgres.CollectionService.Update(%d)", c.ID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		UPDATE collections SET (slug, title, description, publish_from, publish_until, version) = ($1, $2, $3, $4, $5, version+1)
+		WHERE id = $6 AND version = $7
+		RETURNING version`
+	args := []any{c.Slug, c.Title, c.Description, nullTime(c.PublishFrom), nullTime(c.PublishUntil), c.ID, c.Version}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&c.Version); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.NewConflictError("Conflicting change")
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("A collection with this slug already exists.")
+		default:
+			return err
+		}
+	}
+	if err := setCollectionMovies(ctx, tx, c); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CollectionService) Delete(ctx context.Context, id int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.CollectionService.Delete(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `DELETE FROM collections WHERE id = $1`
+	args := []any{id}
+	rs, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CollectionService) Movies(ctx context.Context, id int64, visibleTo *greenlight.User) (_ []*greenlight.Movie, err error) {
+	defer multierr.Wrap(&err, "postgres.CollectionService.Movies(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	cols, dest := movieSelect(nil)
+	args := []any{id}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM collection_movies cm
+		JOIN movies ON movies.id = cm.movie_id
+		WHERE cm.collection_id = $1 AND %s
+		ORDER BY cm.position`, cols, movieVisible(visibleTo, &args))
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var movies []*greenlight.Movie
+	for rs.Next() {
+		var m greenlight.Movie
+		if err := rs.Scan(dest(&m)...); err != nil {
+			return nil, err
+		}
+		movies = append(movies, &m)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// setCollectionMovies replaces the movies of the collection with its [greenlight.Collection.MovieIDs].
+func setCollectionMovies(ctx context.Context, tx *sql.Tx, c *greenlight.Collection) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM collection_movies WHERE collection_id = $1`, c.ID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO collection_movies (collection_id, movie_id, position)
+		SELECT $1, m.id, m.position FROM unnest($2::bigint[]) WITH ORDINALITY AS m(id, position)`
+	if _, err := tx.ExecContext(ctx, query, c.ID, pq.Array(c.MovieIDs)); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation":
+			return greenlight.NewInvalidError("Collection movies must exist.")
+		default:
+			return err
+		}
+	}
+	return nil
+}
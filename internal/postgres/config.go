@@ -8,6 +8,7 @@ type options struct {
 	connMaxIdleTime time.Duration
 	connTimeout     time.Duration
 	queryTimeout    time.Duration
+	keepOnClose     bool
 }
 
 // Option represents a configuration option for PostgreSQL*options.
@@ -47,3 +48,11 @@ func WithQueryTimeout(queryTimeout time.Duration) Option {
 		opts.queryTimeout = queryTimeout
 	}
 }
+
+// WithKeepOnClose sets whether the database schema and data are kept when the database is closed,
+// rather than migrated down.
+func WithKeepOnClose(keep bool) Option {
+	return func(opts *options) {
+		opts.keepOnClose = keep
+	}
+}
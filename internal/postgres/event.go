@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+	"github.com/lib/pq"
+)
+
+// movieChangesChannel is the notification channel of the recorded movie changes.
+const movieChangesChannel = "movie_changes"
+
+// subscriberBufferSize is the number of the changes buffered for a subscriber before it's considered fallen behind.
+const subscriberBufferSize = 64
+
+// MovieEventService represents a service delivering the movie changes backed by PostgreSQL LISTEN/NOTIFY.
+// A single connection listens for the changes, which are fanned out to the subscribers of the instance.
+type MovieEventService struct {
+	db *DB
+
+	listener *pq.Listener
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	subs   map[chan *greenlight.MovieChange]struct{}
+	closed bool
+}
+
+var _ greenlight.MovieEventService = (*MovieEventService)(nil)
+
+// NewMovieEventService returns a new instance of [MovieEventService].
+func NewMovieEventService(db *DB) *MovieEventService {
+	return &MovieEventService{
+		db:   db,
+		done: make(chan struct{}),
+		subs: make(map[chan *greenlight.MovieChange]struct{}),
+	}
+}
+
+// Open starts listening for the movie changes.
+func (s *MovieEventService) Open() (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieEventService.Open")
+
+	s.listener = pq.NewListener(s.db.DSN, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			s.db.logger.Error("listening for movie changes", "event", ev, "error", err)
+		}
+	})
+	if err := s.listener.Listen(movieChangesChannel); err != nil {
+		return multierr.Join(err, s.listener.Close())
+	}
+
+	s.wg.Add(1)
+	go s.listen()
+	return nil
+}
+
+// Close stops listening for the movie changes and closes the channels of all the subscribers.
+func (s *MovieEventService) Close() (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieEventService.Close")
+
+	close(s.done)
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.dropAll()
+	return err
+}
+
+func (s *MovieEventService) Subscribe(ctx context.Context) <-chan *greenlight.MovieChange {
+	ch := make(chan *greenlight.MovieChange, subscriberBufferSize)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(ch)
+		return ch
+	}
+	s.subs[ch] = struct{}{}
+	context.AfterFunc(ctx, func() { s.unsubscribe(ch) })
+	return ch
+}
+
+// listen fans out the notifications of the listener until the service is closed.
+func (s *MovieEventService) listen() {
+	defer s.wg.Done()
+
+	// The connection is checked periodically, since a broken connection isn't noticed otherwise.
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			go func() { _ = s.listener.Ping() }()
+		case n, ok := <-s.listener.Notify:
+			if !ok {
+				return
+			}
+			// A nil notification is sent after the connection is re-established, notifications may have been missed.
+			if n == nil {
+				s.mu.Lock()
+				s.dropAll()
+				s.mu.Unlock()
+				continue
+			}
+			c, err := parseMovieChange(n.Extra)
+			if err != nil {
+				s.db.logger.Error("parsing movie change notification", "payload", n.Extra, "error", err)
+				continue
+			}
+			s.publish(c)
+		}
+	}
+}
+
+// publish sends the change to all the subscribers. Subscribers that fell behind are dropped.
+func (s *MovieEventService) publish(c *greenlight.MovieChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subs {
+		select {
+		case ch <- c:
+		default:
+			delete(s.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (s *MovieEventService) unsubscribe(ch chan *greenlight.MovieChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subs[ch]; ok {
+		delete(s.subs, ch)
+		close(ch)
+	}
+}
+
+// dropAll closes the channels of all the subscribers. It must be called with the mutex held.
+func (s *MovieEventService) dropAll() {
+	for ch := range s.subs {
+		close(ch)
+	}
+	clear(s.subs)
+}
+
+// parseMovieChange parses the notification payload, which is a row of the movie_changes table in JSON.
+func parseMovieChange(payload string) (*greenlight.MovieChange, error) {
+	var row struct {
+		Seq       int64                    `json:"seq"`
+		MovieID   int64                    `json:"movie_id"`
+		Op        greenlight.MovieChangeOp `json:"op"`
+		Version   int32                    `json:"version"`
+		ChangedAt time.Time                `json:"changed_at"`
+	}
+	if err := json.Unmarshal([]byte(payload), &row); err != nil {
+		return nil, err
+	}
+	return &greenlight.MovieChange{
+		Seq:       row.Seq,
+		MovieID:   row.MovieID,
+		Op:        row.Op,
+		Version:   row.Version,
+		ChangedAt: row.ChangedAt,
+	}, nil
+}
@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+	"github.com/lib/pq"
+)
+
+// GenreService represents a service for managing genres backed by PostgreSQL.
+type GenreService struct {
+	db *DB
+}
+
+var _ greenlight.GenreService = (*GenreService)(nil)
+
+// NewGenreService returns a new instance of [GenreService].
+func NewGenreService(db *DB) *GenreService {
+	return &GenreService{
+		db: db,
+	}
+}
+
+func (s *GenreService) Get(ctx context.Context, slug string) (_ *greenlight.Genre, err error) {
+	defer multierr.Wrap(&err, "postgres.GenreService.Get(%q)", slug)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT slug, name FROM genres WHERE slug = $1`
+	var g greenlight.Genre
+	if err := tx.QueryRowContext(ctx, query, slug).Scan(&g.Slug, &g.Name); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (s *GenreService) GetAll(ctx context.Context) (_ []*greenlight.Genre, err error) {
+	defer multierr.Wrap(&err, "postgres.GenreService.GetAll")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT slug, name FROM genres ORDER BY name ASC, slug ASC`
+	rs, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	genres := []*greenlight.Genre{}
+	for rs.Next() {
+		var g greenlight.Genre
+		if err := rs.Scan(&g.Slug, &g.Name); err != nil {
+			return nil, err
+		}
+		genres = append(genres, &g)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+func (s *GenreService) Create(ctx context.Context, g *greenlight.Genre) (err error) {
+	defer multierr.Wrap(&err, "postgres.GenreService.Create")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO genres (slug, name) VALUES ($1, $2)`
+	if _, err := tx.ExecContext(ctx, query, g.Slug, g.Name); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("A genre with this slug already exists.")
+		default:
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *GenreService) Rename(ctx context.Context, slug string, g *greenlight.Genre) (err error) {
+	defer multierr.Wrap(&err, "postgres.GenreService.Rename(%q)", slug)
+
+	// Renaming rewrites all the movies of the genre in a single transaction, so that the catalogue stays consistent.
+	// It may take long for large catalogues, so it isn't limited by the query timeout.
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `UPDATE genres SET (slug, name) = ($1, $2) WHERE slug = $3`
+	rs, err := tx.ExecContext(ctx, query, g.Slug, g.Name, slug)
+	if err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("A genre with this slug already exists.")
+		default:
+			return err
+		}
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+
+	if g.Slug != slug {
+		query = `UPDATE movies SET genres = array_replace(genres, $1, $2), version = version+1 WHERE genres @> ARRAY[$1]`
+		if _, err := tx.ExecContext(ctx, query, slug, g.Slug); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *GenreService) Merge(ctx context.Context, slug, into string) (err error) {
+	defer multierr.Wrap(&err, "postgres.GenreService.Merge(%q, %q)", slug, into)
+
+	if slug == into {
+		return greenlight.NewInvalidError("A genre can't be merged into itself.")
+	}
+
+	// Like renaming, merging rewrites all the movies of the genre, so it isn't limited by the query timeout.
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `DELETE FROM genres WHERE slug = $1`
+	rs, err := tx.ExecContext(ctx, query, slug)
+	if err != nil {
+		return err
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+
+	unknown, err := unknownGenres(ctx, tx, []string{into})
+	if err != nil {
+		return err
+	}
+	if len(unknown) != 0 {
+		return greenlight.NewInvalidError("Genre %q doesn't exist.", into)
+	}
+
+	// Movies already having the target genre just lose the merged one.
+	query = `
+		UPDATE movies
+		SET genres = CASE WHEN genres @> ARRAY[$2] THEN array_remove(genres, $1) ELSE array_replace(genres, $1, $2) END,
+			version = version+1
+		WHERE genres @> ARRAY[$1]`
+	if _, err := tx.ExecContext(ctx, query, slug, into); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// unknownGenres returns those of the slugs that don't identify a genre.
+// Existing genres are share-locked so they can't be renamed or merged until the transaction ends.
+func unknownGenres(ctx context.Context, tx *sql.Tx, slugs []string) ([]string, error) {
+	query := `SELECT 1 FROM genres WHERE slug = ANY($1) FOR SHARE`
+	if _, err := tx.ExecContext(ctx, query, pq.Array(slugs)); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT ARRAY(SELECT s FROM unnest($1::text[]) AS s WHERE NOT EXISTS (SELECT 1 FROM genres WHERE slug = s))`
+	var unknown []string
+	if err := tx.QueryRowContext(ctx, query, pq.Array(slugs)).Scan(pq.Array(&unknown)); err != nil {
+		return nil, err
+	}
+	return unknown, nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonb stores and scans values of jsonb columns. A JSON null is stored as a NULL.
+type jsonb struct {
+	v any
+}
+
+// Value implements the [driver.Valuer] interface.
+func (j jsonb) Value() (driver.Value, error) {
+	js, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, err
+	}
+	if string(js) == "null" {
+		return nil, nil
+	}
+	return js, nil
+}
+
+// Scan implements the [sql.Scanner] interface.
+func (j jsonb) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(src, j.v)
+	case string:
+		return json.Unmarshal([]byte(src), j.v)
+	default:
+		return fmt.Errorf("unsupported jsonb type %T", src)
+	}
+}
@@ -0,0 +1,423 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+	"github.com/lib/pq"
+)
+
+// ListService represents a service for managing user lists backed by PostgreSQL.
+type ListService struct {
+	db *DB
+}
+
+var _ greenlight.ListService = (*ListService)(nil)
+
+// NewListService returns a new instance of [ListService].
+func NewListService(db *DB) *ListService {
+	return &ListService{
+		db: db,
+	}
+}
+
+func (s *ListService) Get(ctx context.Context, id int64) (_ *greenlight.List, err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.Get(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT id, user_id, name, watchlist, visibility, created_at, version FROM lists WHERE id = $1`
+	args := []any{id}
+	var l greenlight.List
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&l.ID, &l.UserID, &l.Name, &l.Watchlist, &l.Visibility, &l.CreatedAt, &l.Version); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (s *ListService) GetAll(ctx context.Context, userID int64, public bool) (_ []*greenlight.List, err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.GetAll(%d)", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		SELECT id, user_id, name, watchlist, visibility, created_at, version
+		FROM lists
+		WHERE user_id = $1 AND (visibility = 'public' OR NOT $2)
+		ORDER BY watchlist DESC, id ASC`
+	rs, err := tx.QueryContext(ctx, query, userID, public)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var lists []*greenlight.List
+	for rs.Next() {
+		var l greenlight.List
+		if err := rs.Scan(&l.ID, &l.UserID, &l.Name, &l.Watchlist, &l.Visibility, &l.CreatedAt, &l.Version); err != nil {
+			return nil, err
+		}
+		lists = append(lists, &l)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (s *ListService) Watchlist(ctx context.Context, userID int64) (_ *greenlight.List, err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.Watchlist(%d)", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO lists (user_id, name, watchlist, visibility) VALUES ($1, $2, TRUE, 'private')
+		ON CONFLICT (user_id) WHERE watchlist DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, userID, greenlight.WatchlistName); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT id, user_id, name, watchlist, visibility, created_at, version FROM lists WHERE user_id = $1 AND watchlist`
+	var l greenlight.List
+	if err := tx.QueryRowContext(ctx, query, userID).Scan(&l.ID, &l.UserID, &l.Name, &l.Watchlist, &l.Visibility, &l.CreatedAt, &l.Version); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (s *ListService) Create(ctx context.Context, l *greenlight.List) (err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.Create")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO lists (user_id, name, visibility) VALUES ($1, $2, $3) RETURNING id, created_at, version`
+	args := []any{l.UserID, l.Name, l.Visibility}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&l.ID, &l.CreatedAt, &l.Version); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("A list with this name already exists.")
+		default:
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ListService) Update(ctx context.Context, l *greenlight.List) (err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.Update(%d)", l.ID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `UPDATE lists SET (name, visibility, version) = ($1, $2, version+1) WHERE id = $3 AND version = $4 RETURNING version`
+	args := []any{l.Name, l.Visibility, l.ID, l.Version}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&l.Version); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.NewConflictError("Conflicting change")
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("A list with this name already exists.")
+		default:
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ListService) Delete(ctx context.Context, id int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.Delete(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var watchlist bool
+	if err := tx.QueryRowContext(ctx, `DELETE FROM lists WHERE id = $1 RETURNING watchlist`, id).Scan(&watchlist); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.ErrNotFound
+		default:
+			return err
+		}
+	}
+	if watchlist {
+		return greenlight.NewConflictError("The watchlist can't be deleted.")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ListService) Entries(ctx context.Context, listID int64, visibleTo *greenlight.User) (_ []*greenlight.ListEntry, err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.Entries(%d)", listID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	cols, dest := movieSelect(nil)
+	args := []any{listID}
+	query := fmt.Sprintf(`
+		SELECT e.list_id, e.movie_id, e.position, e.notes, e.added_at, %s
+		FROM list_entries e
+		JOIN movies ON movies.id = e.movie_id
+		WHERE e.list_id = $1 AND %s
+		ORDER BY e.position`, cols, movieVisible(visibleTo, &args))
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var entries []*greenlight.ListEntry
+	for rs.Next() {
+		e := greenlight.ListEntry{Movie: &greenlight.Movie{}}
+		args := append([]any{&e.ListID, &e.MovieID, &e.Position, &e.Notes, &e.AddedAt}, dest(e.Movie)...)
+		if err := rs.Scan(args...); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *ListService) AddEntry(ctx context.Context, e *greenlight.ListEntry) (err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.AddEntry(%d, %d)", e.ListID, e.MovieID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	end, err := lockList(ctx, tx, e.ListID)
+	if err != nil {
+		return err
+	}
+	if e.Position == 0 || e.Position > end {
+		e.Position = end
+	}
+	if err := shiftListEntries(ctx, tx, e.ListID, e.Position, 1); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO list_entries (list_id, movie_id, position, notes) VALUES ($1, $2, $3, $4) RETURNING added_at`
+	args := []any{e.ListID, e.MovieID, e.Position, e.Notes}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&e.AddedAt); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("The movie is already in the list.")
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation":
+			return greenlight.NewInvalidError("Movie %d doesn't exist.", e.MovieID)
+		default:
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ListService) UpdateEntry(ctx context.Context, e *greenlight.ListEntry) (err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.UpdateEntry(%d, %d)", e.ListID, e.MovieID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	end, err := lockList(ctx, tx, e.ListID)
+	if err != nil {
+		return err
+	}
+
+	var pos int
+	query := `DELETE FROM list_entries WHERE list_id = $1 AND movie_id = $2 RETURNING position, added_at`
+	if err := tx.QueryRowContext(ctx, query, e.ListID, e.MovieID).Scan(&pos, &e.AddedAt); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.ErrNotFound
+		default:
+			return err
+		}
+	}
+	if err := shiftListEntries(ctx, tx, e.ListID, pos+1, -1); err != nil {
+		return err
+	}
+
+	// The entry is removed, so the end of the list moved by one.
+	if e.Position == 0 {
+		e.Position = pos
+	}
+	if e.Position > end-1 {
+		e.Position = end - 1
+	}
+	if err := shiftListEntries(ctx, tx, e.ListID, e.Position, 1); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO list_entries (list_id, movie_id, position, notes, added_at) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, query, e.ListID, e.MovieID, e.Position, e.Notes, e.AddedAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ListService) RemoveEntry(ctx context.Context, listID, movieID int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.ListService.RemoveEntry(%d, %d)", listID, movieID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := lockList(ctx, tx, listID); err != nil {
+		return err
+	}
+
+	var pos int
+	query := `DELETE FROM list_entries WHERE list_id = $1 AND movie_id = $2 RETURNING position`
+	if err := tx.QueryRowContext(ctx, query, listID, movieID).Scan(&pos); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.ErrNotFound
+		default:
+			return err
+		}
+	}
+	if err := shiftListEntries(ctx, tx, listID, pos+1, -1); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// lockList locks the list until the end of the transaction, serializing changes of the entry positions.
+// It returns the position following the last entry of the list.
+func lockList(ctx context.Context, tx *sql.Tx, listID int64) (end int, err error) {
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM lists WHERE id = $1 FOR UPDATE`, listID).Scan(&listID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, greenlight.ErrNotFound
+		default:
+			return 0, err
+		}
+	}
+	query := `SELECT COALESCE(MAX(position), 0) + 1 FROM list_entries WHERE list_id = $1`
+	if err := tx.QueryRowContext(ctx, query, listID).Scan(&end); err != nil {
+		return 0, err
+	}
+	return end, nil
+}
+
+// shiftListEntries moves the entries of the list starting from the position by the delta.
+func shiftListEntries(ctx context.Context, tx *sql.Tx, listID int64, from, delta int) error {
+	query := `UPDATE list_entries SET position = position + $3 WHERE list_id = $1 AND position >= $2`
+	_, err := tx.ExecContext(ctx, query, listID, from, delta)
+	return err
+}
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS people (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    birth_date date,
+    biography text NOT NULL DEFAULT '',
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS credits (
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    person_id bigint NOT NULL REFERENCES people ON DELETE CASCADE,
+    role text NOT NULL CHECK (role IN ('actor', 'director', 'writer')),
+    character text NOT NULL DEFAULT '',
+    billing integer NOT NULL CHECK (billing >= 1),
+    PRIMARY KEY (movie_id, person_id, role, character)
+);
+
+CREATE INDEX IF NOT EXISTS credits_person_id_idx ON credits (person_id);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS lists (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    name text NOT NULL,
+    watchlist boolean NOT NULL DEFAULT FALSE,
+    visibility text NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'unlisted', 'public')),
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, name)
+);
+
+-- Every user has at most one watchlist.
+CREATE UNIQUE INDEX IF NOT EXISTS lists_watchlist_idx ON lists (user_id) WHERE watchlist;
+
+CREATE TABLE IF NOT EXISTS list_entries (
+    list_id bigint NOT NULL REFERENCES lists ON DELETE CASCADE,
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    position integer NOT NULL CHECK (position >= 1),
+    notes text NOT NULL DEFAULT '',
+    added_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, movie_id),
+    -- Deferred, so entries can be shifted by a single UPDATE.
+    UNIQUE (list_id, position) DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS collections (
+    id bigserial PRIMARY KEY,
+    slug text UNIQUE NOT NULL,
+    title text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    publish_from timestamp(0) with time zone,
+    publish_until timestamp(0) with time zone,
+    version integer NOT NULL DEFAULT 1,
+    CHECK (publish_until > publish_from)
+);
+
+CREATE TABLE IF NOT EXISTS collection_movies (
+    collection_id bigint NOT NULL REFERENCES collections ON DELETE CASCADE,
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    position integer NOT NULL,
+    PRIMARY KEY (collection_id, movie_id)
+);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS genres (
+    slug text PRIMARY KEY CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    name text NOT NULL
+);
+
+-- Backfill the genres from the movies, collapsing spellings of the same genre, e.g. "Sci-Fi" and "sci fi", into one slug.
+INSERT INTO genres (slug, name)
+SELECT DISTINCT ON (slug) slug, initcap(trim(g))
+FROM movies, unnest(genres) AS g, trim(BOTH '-' FROM lower(regexp_replace(g, '[^a-zA-Z0-9]+', '-', 'g'))) AS slug
+WHERE slug <> ''
+ORDER BY slug, g
+ON CONFLICT DO NOTHING;
+
+UPDATE movies SET genres = ARRAY(
+    SELECT slug
+    FROM unnest(genres) WITH ORDINALITY AS t(g, n), trim(BOTH '-' FROM lower(regexp_replace(g, '[^a-zA-Z0-9]+', '-', 'g'))) AS slug
+    WHERE slug <> ''
+    GROUP BY slug
+    ORDER BY min(n)
+)
+-- Movies having no genre with a letter or digit would be left without genres, so they keep theirs.
+WHERE array_to_string(genres, '') ~ '[a-zA-Z0-9]';
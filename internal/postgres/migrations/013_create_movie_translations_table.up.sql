@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS movie_translations (
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    language text NOT NULL,
+    title text NOT NULL,
+    synopsis text NOT NULL DEFAULT '',
+    PRIMARY KEY (movie_id, language)
+);
+
+CREATE INDEX IF NOT EXISTS movie_translations_title_idx ON movie_translations (LOWER(title));
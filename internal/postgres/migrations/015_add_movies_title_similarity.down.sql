@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS movies_title_trgm_idx;
+DROP FUNCTION IF EXISTS normalize_title(text);
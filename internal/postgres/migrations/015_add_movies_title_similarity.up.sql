@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- normalize_title returns the title in the form compared by the duplicate detection:
+-- lowercase, without punctuation and a leading article, with single spaces.
+CREATE OR REPLACE FUNCTION normalize_title(title text) RETURNS text AS $$
+    SELECT regexp_replace(trim(regexp_replace(lower(title), '[^[:alnum:]]+', ' ', 'g')), '^(the|an|a) ', '')
+$$ LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE;
+
+CREATE INDEX IF NOT EXISTS movies_title_trgm_idx ON movies USING GIN (normalize_title(title) gin_trgm_ops);
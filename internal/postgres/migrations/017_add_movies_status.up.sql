@@ -0,0 +1,8 @@
+-- Existing movies were visible to everyone before the workflow, so the column is added with the 'published' default
+-- to backfill them as published and keep them visible. The default is then switched to 'draft', so that new movies
+-- are hidden until they're published.
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+ALTER TABLE movies ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS publish_at timestamp(0) with time zone;
+
+CREATE INDEX IF NOT EXISTS movies_status_idx ON movies (status);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS movie_certifications (
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    country text NOT NULL,
+    rating text NOT NULL,
+    PRIMARY KEY (movie_id, country)
+);
+
+CREATE INDEX IF NOT EXISTS movie_certifications_country_rating_idx ON movie_certifications (country, rating);
+
+-- The maximum certification of the movies shown to the user in the "COUNTRY:RATING" format.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_certification text;
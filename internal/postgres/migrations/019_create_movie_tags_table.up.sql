@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS movie_tags (
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    tag text NOT NULL,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, tag, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS movie_tags_tag_idx ON movie_tags (tag text_pattern_ops);
+
+-- Distinct tags of the movie, maintained along with the tags.
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS tags text[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS movies_tags_idx ON movies USING GIN (tags);
@@ -0,0 +1,24 @@
+-- Statistics of the catalogue, refreshed periodically. The single row is keyed by id, so it can be refreshed concurrently.
+CREATE MATERIALIZED VIEW IF NOT EXISTS movie_stats AS
+WITH published AS (
+    SELECT id, genres, release_date, runtime FROM movies
+    WHERE status = 'published' AND (publish_at IS NULL OR publish_at <= NOW())
+)
+SELECT
+    1 AS id,
+    (SELECT COUNT(*) FROM published) AS total,
+    (SELECT COALESCE(jsonb_object_agg(status, n), '{}') FROM (
+        SELECT status, COUNT(*) AS n FROM movies GROUP BY status
+    ) s) AS by_status,
+    (SELECT COALESCE(jsonb_object_agg(g, n), '{}') FROM (
+        SELECT g, COUNT(*) AS n FROM published, unnest(genres) AS g GROUP BY g
+    ) s) AS by_genre,
+    (SELECT COALESCE(jsonb_object_agg(d, n), '{}') FROM (
+        SELECT (EXTRACT(YEAR FROM release_date)::int / 10 * 10)::text AS d, COUNT(*) AS n FROM published GROUP BY d
+    ) s) AS by_decade,
+    (SELECT COALESCE(AVG(runtime), 0)::float8 FROM published) AS runtime_avg,
+    (SELECT COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY runtime), 0)::float8 FROM published) AS runtime_median,
+    ARRAY(SELECT id FROM published ORDER BY id DESC LIMIT 10) AS recent_ids,
+    NOW() AS refreshed_at;
+
+CREATE UNIQUE INDEX IF NOT EXISTS movie_stats_id_idx ON movie_stats (id);
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS movie_changes (
+    seq bigserial PRIMARY KEY,
+    movie_id bigint NOT NULL,
+    op text NOT NULL,
+    version integer NOT NULL,
+    changed_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+-- Changes are recorded to the log without serializing the transactions making them, and are appended
+-- to the movie_changes feed by a single sequencer after they're committed, so that the positions of the feed
+-- follow the commit order, see MovieService.SequenceChanges.
+CREATE TABLE IF NOT EXISTS movie_change_log (
+    id bigserial PRIMARY KEY,
+    movie_id bigint NOT NULL,
+    op text NOT NULL,
+    version integer NOT NULL,
+    changed_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+-- record_movie_change records the change of a movie version to the log in the transaction making the change,
+-- so that bulk changes and changes made outside the movie service are recorded as well.
+CREATE OR REPLACE FUNCTION record_movie_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO movie_change_log (movie_id, op, version) VALUES (OLD.id, 'deleted', OLD.version);
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.version = OLD.version THEN
+        RETURN NEW;
+    END IF;
+    INSERT INTO movie_change_log (movie_id, op, version)
+    VALUES (NEW.id, CASE TG_OP WHEN 'INSERT' THEN 'created' ELSE 'updated' END, NEW.version);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS movies_record_change ON movies;
+CREATE TRIGGER movies_record_change AFTER INSERT OR UPDATE OF version OR DELETE ON movies
+FOR EACH ROW EXECUTE FUNCTION record_movie_change();
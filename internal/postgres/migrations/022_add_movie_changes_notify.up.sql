@@ -0,0 +1,12 @@
+-- The changes are sent to the listeners of the "movie_changes" channel as they're appended to the feed,
+-- on commit in the sequence order.
+CREATE OR REPLACE FUNCTION notify_movie_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('movie_changes', row_to_json(NEW)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS movie_changes_notify ON movie_changes;
+CREATE TRIGGER movie_changes_notify AFTER INSERT ON movie_changes
+FOR EACH ROW EXECUTE FUNCTION notify_movie_change();
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS movie_changes_enqueue_webhooks ON movie_changes;
+DROP FUNCTION IF EXISTS enqueue_webhook_deliveries();
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    url text NOT NULL,
+    events text[] NOT NULL DEFAULT '{}',
+    secret text NOT NULL,
+    active boolean NOT NULL DEFAULT TRUE,
+    failures integer NOT NULL DEFAULT 0,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_user_id_idx ON webhooks (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bigserial PRIMARY KEY,
+    webhook_id bigint NOT NULL REFERENCES webhooks ON DELETE CASCADE,
+    change_seq bigint NOT NULL REFERENCES movie_changes ON DELETE CASCADE,
+    status text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    status_code integer,
+    error text,
+    last_attempt_at timestamp with time zone,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+-- enqueue_webhook_deliveries creates the deliveries of the recorded movie change to the matching active webhooks,
+-- so that the deliveries are created in the transaction of the change.
+CREATE OR REPLACE FUNCTION enqueue_webhook_deliveries() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (webhook_id, change_seq)
+    SELECT id, NEW.seq FROM webhooks WHERE active AND (events = '{}' OR NEW.op = ANY (events));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS movie_changes_enqueue_webhooks ON movie_changes;
+CREATE TRIGGER movie_changes_enqueue_webhooks AFTER INSERT ON movie_changes
+FOR EACH ROW EXECUTE FUNCTION enqueue_webhook_deliveries();
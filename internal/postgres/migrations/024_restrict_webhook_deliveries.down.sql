@@ -0,0 +1,7 @@
+CREATE OR REPLACE FUNCTION enqueue_webhook_deliveries() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (webhook_id, change_seq)
+    SELECT id, NEW.seq FROM webhooks WHERE active AND (events = '{}' OR NEW.op = ANY (events));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
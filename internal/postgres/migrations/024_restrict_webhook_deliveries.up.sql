@@ -0,0 +1,11 @@
+-- The changes of all the movies, including the unpublished ones, are only delivered to the webhooks of the editors,
+-- so that the webhooks of the users that are no longer editors don't receive them.
+CREATE OR REPLACE FUNCTION enqueue_webhook_deliveries() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (webhook_id, change_seq)
+    SELECT w.id, NEW.seq
+    FROM webhooks w JOIN users u ON u.id = w.user_id
+    WHERE w.active AND u.role IN ('editor', 'admin') AND (w.events = '{}' OR NEW.op = ANY (w.events));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -1,11 +1,14 @@
 package postgres
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/denpeshkov/greenlight/internal/greenlight"
 	"github.com/denpeshkov/greenlight/internal/multierr"
@@ -38,10 +41,11 @@ func (s *MovieService) Get(ctx context.Context, id int64) (_ *greenlight.Movie,
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query := `SELECT id, title, release_date, runtime, genres, version FROM movies WHERE id = $1`
+	cols, dest := movieSelect(nil)
+	query := fmt.Sprintf(`SELECT %s FROM movies WHERE id = $1`, cols)
 	args := []any{id}
 	var m greenlight.Movie
-	if err := tx.QueryRowContext(ctx, query, args...).Scan(&m.ID, &m.Title, &m.ReleaseDate, &m.Runtime, pq.Array(&m.Genres), &m.Version); err != nil {
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(dest(&m)...); err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return nil, greenlight.ErrNotFound
@@ -49,6 +53,9 @@ func (s *MovieService) Get(ctx context.Context, id int64) (_ *greenlight.Movie,
 			return nil, err
 		}
 	}
+	if err := localizeMovies(ctx, tx, []*greenlight.Movie{&m}); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -74,13 +81,236 @@ func (s *MovieService) GetAll(ctx context.Context, filter greenlight.MovieFilter
 		sortDir = "DESC"
 	}
 
+	cols, dest := movieSelect(filter.Fields)
+	where, args := movieWhere(filter)
 	query := fmt.Sprintf(`
-		SELECT id, title, release_date, runtime, genres, version 
+		SELECT %s
 		FROM movies
-		WHERE (LOWER(title) = LOWER($1) OR $1 = '') AND (genres @> $2 OR $2 = '{}')
+		WHERE %s
 		ORDER BY %s %s, id ASC
-		LIMIT $3 OFFSET $4`, sortCol, sortDir)
-	rs, err := tx.QueryContext(ctx, query, filter.Title, pq.Array(filter.Genres), filter.PageSize, (filter.Page-1)*filter.PageSize)
+		LIMIT $%d OFFSET $%d`, cols, where, sortCol, sortDir, len(args)+1, len(args)+2)
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var movies []*greenlight.Movie
+	for rs.Next() {
+		var m greenlight.Movie
+		if err := rs.Scan(dest(&m)...); err != nil {
+			return nil, err
+		}
+		movies = append(movies, &m)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+	if err := localizeMovies(ctx, tx, movies); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// exportBatchSize is the number of rows fetched from the export cursor at once.
+const exportBatchSize = 1000
+
+func (s *MovieService) Export(ctx context.Context, filter greenlight.MovieFilter, fn func(m *greenlight.Movie) error) (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Export")
+
+	// The export may take long, so the query timeout applies to each of the fetches rather than to the transaction.
+	tx, err := s.db.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sortCol, sortDir := filter.Sort, "ASC"
+	if v, ok := strings.CutPrefix(sortCol, "-"); ok {
+		sortCol = v
+		sortDir = "DESC"
+	}
+
+	cols, dest := movieSelect(nil)
+	where, args := movieWhere(filter)
+	query := fmt.Sprintf(`
+		DECLARE movies_export NO SCROLL CURSOR FOR
+		SELECT %s
+		FROM movies
+		WHERE %s
+		ORDER BY %s %s, id ASC`, cols, where, sortCol, sortDir)
+	if err := s.execTimeout(ctx, tx, query, args...); err != nil {
+		return err
+	}
+
+	// Rows are fetched in batches, so the memory use doesn't depend on the number of the exported movies.
+	for {
+		movies, err := s.fetch(ctx, tx, dest)
+		if err != nil {
+			return err
+		}
+		for _, m := range movies {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+		if len(movies) < exportBatchSize {
+			break
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// execTimeout executes the query in the transaction with the query timeout.
+func (s *MovieService) execTimeout(ctx context.Context, tx *sql.Tx, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// fetch returns the next batch of the export cursor movies scanned using the dest.
+func (s *MovieService) fetch(ctx context.Context, tx *sql.Tx, dest func(m *greenlight.Movie) []any) ([]*greenlight.Movie, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	rs, err := tx.QueryContext(ctx, fmt.Sprintf(`FETCH FORWARD %d FROM movies_export`, exportBatchSize))
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	movies := make([]*greenlight.Movie, 0, exportBatchSize)
+	for rs.Next() {
+		var m greenlight.Movie
+		if err := rs.Scan(dest(&m)...); err != nil {
+			return nil, err
+		}
+		movies = append(movies, &m)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (s *MovieService) Facets(ctx context.Context, filter greenlight.MovieFilter) (_ greenlight.MovieFacets, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Facets")
+
+	facets := make(greenlight.MovieFacets, len(filter.Facets))
+	if len(filter.Facets) == 0 {
+		return facets, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// All the facets are counted in a single pass over the filtered movies.
+	var counts []string
+	for _, f := range filter.Facets {
+		facets[f] = make(map[string]int)
+		switch f {
+		case greenlight.FacetGenres:
+			counts = append(counts, `SELECT 'genres', g, COUNT(*) FROM filtered, unnest(genres) AS g GROUP BY g`)
+		case greenlight.FacetDecade:
+			counts = append(counts, `SELECT 'decade', (EXTRACT(YEAR FROM release_date)::int / 10 * 10)::text AS d, COUNT(*) FROM filtered GROUP BY d`)
+		case greenlight.FacetRuntimeBucket:
+			counts = append(counts, `
+				SELECT 'runtime_bucket', CASE
+					WHEN runtime < 90 THEN '0-89'
+					WHEN runtime < 120 THEN '90-119'
+					WHEN runtime < 150 THEN '120-149'
+					ELSE '150+'
+				END AS b, COUNT(*)
+				FROM filtered GROUP BY b`)
+		}
+	}
+
+	where, args := movieWhere(filter)
+	query := fmt.Sprintf(`
+		WITH filtered AS MATERIALIZED (SELECT genres, release_date, runtime FROM movies WHERE %s)
+		%s`, where, strings.Join(counts, " UNION ALL "))
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		var facet, value string
+		var n int
+		if err := rs.Scan(&facet, &value, &n); err != nil {
+			return nil, err
+		}
+		facets[facet][value] = n
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+func (s *MovieService) Similar(ctx context.Context, filter greenlight.SimilarMovieFilter) (_ []*greenlight.Movie, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Similar(%d)", filter.MovieID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	args := []any{filter.MovieID}
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1 AND %s)`, movieVisible(filter.VisibleTo, &args))
+	var exists bool
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, greenlight.ErrNotFound
+	}
+
+	// The score is a weighted sum of the genres Jaccard index, the release era proximity
+	// that halves every 10 years apart, and the relative runtime difference, each in [0, 1].
+	// Only movies sharing a genre are ranked so the candidates are selected using the genres index.
+	cols, dest := movieSelect(filter.Fields)
+	args = []any{filter.MovieID, filter.PageSize, (filter.Page - 1) * filter.PageSize}
+	visible := movieVisible(filter.VisibleTo, &args)
+	query = fmt.Sprintf(`
+		SELECT %s
+		FROM movies, (SELECT genres, release_date, runtime FROM movies WHERE id = $1) AS src
+		WHERE movies.id <> $1 AND movies.genres && src.genres AND %s
+		ORDER BY
+			0.6 * cardinality(ARRAY(SELECT unnest(movies.genres) INTERSECT SELECT unnest(src.genres)))::float8
+				/ cardinality(ARRAY(SELECT unnest(movies.genres) UNION SELECT unnest(src.genres)))
+			+ 0.25 / (1 + abs(movies.release_date - src.release_date) / 3652.5)
+			+ 0.15 * (1 - abs(movies.runtime - src.runtime)::float8 / greatest(movies.runtime, src.runtime))
+			DESC,
+			movies.id ASC
+		LIMIT $2 OFFSET $3`, cols, visible)
+	rs, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +319,7 @@ func (s *MovieService) GetAll(ctx context.Context, filter greenlight.MovieFilter
 	var movies []*greenlight.Movie
 	for rs.Next() {
 		var m greenlight.Movie
-		if err := rs.Scan(&m.ID, &m.Title, &m.ReleaseDate, &m.Runtime, pq.Array(&m.Genres), &m.Version); err != nil {
+		if err := rs.Scan(dest(&m)...); err != nil {
 			return nil, err
 		}
 		movies = append(movies, &m)
@@ -97,6 +327,9 @@ func (s *MovieService) GetAll(ctx context.Context, filter greenlight.MovieFilter
 	if err := rs.Err(); err != nil {
 		return nil, err
 	}
+	if err := localizeMovies(ctx, tx, movies); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -116,8 +349,15 @@ func (s *MovieService) Update(ctx context.Context, m *greenlight.Movie) (err err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query := `UPDATE movies SET (title, release_date, runtime, genres, version) = ($1, $2, $3, $4, version+1) WHERE id = $5 AND version = $6 RETURNING version`
-	args := []any{m.Title, m.ReleaseDate, m.Runtime, pq.Array(m.Genres), m.ID, m.Version}
+	if err := validGenres(ctx, tx, m.Genres); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE movies SET (title, release_date, runtime, genres, poster, status, publish_at, version) = ($1, $2, $3, $4, $5, $6, $7, version+1)
+		WHERE id = $8 AND version = $9
+		RETURNING version`
+	args := []any{m.Title, m.ReleaseDate, m.Runtime, pq.Array(m.Genres), jsonb{m.Poster}, m.Status, nullTime(m.PublishAt), m.ID, m.Version}
 	if err := tx.QueryRowContext(ctx, query, args...).Scan(&m.Version); err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -126,6 +366,12 @@ func (s *MovieService) Update(ctx context.Context, m *greenlight.Movie) (err err
 			return err
 		}
 	}
+	if err := setExternalIDs(ctx, tx, m); err != nil {
+		return err
+	}
+	if err := setCertifications(ctx, tx, m); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return err
@@ -133,8 +379,8 @@ func (s *MovieService) Update(ctx context.Context, m *greenlight.Movie) (err err
 	return nil
 }
 
-func (s *MovieService) Delete(ctx context.Context, id int64) (err error) {
-	defer multierr.Wrap(&err, "postgres.MovieService.Delete(%d)", id)
+func (s *MovieService) Import(ctx context.Context, movies []*greenlight.Movie) (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Import")
 
 	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
 	defer cancel()
@@ -145,19 +391,79 @@ func (s *MovieService) Delete(ctx context.Context, id int64) (err error) {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query := `DELETE FROM movies where id = $1`
-	args := []any{id}
-	rs, err := tx.ExecContext(ctx, query, args...)
-	if err != nil {
+	// The genres of all the movies are checked at once, before any movie is copied.
+	var genres []string
+	for _, m := range movies {
+		genres = append(genres, m.Genres...)
+	}
+	slices.Sort(genres)
+	if err := validGenres(ctx, tx, slices.Compact(genres)); err != nil {
 		return err
 	}
 
-	n, err := rs.RowsAffected()
+	// IDs are allocated from the movies sequence up front, since COPY can't return them,
+	// and the external IDs and certifications of the movies reference them.
+	var ids []int64
+	query := `SELECT ARRAY(SELECT nextval(pg_get_serial_sequence('movies', 'id')) FROM generate_series(1, $1))`
+	if err := tx.QueryRowContext(ctx, query, len(movies)).Scan(pq.Array(&ids)); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("movies", "id", "title", "release_date", "runtime", "genres", "status", "created_by"))
 	if err != nil {
 		return err
 	}
-	if n == 0 {
-		return greenlight.ErrNotFound
+	defer stmt.Close()
+
+	userID := greenlight.UserIDFromContext(ctx)
+	createdBy := sql.NullInt64{Int64: userID, Valid: userID != 0}
+	for i, m := range movies {
+		status := cmp.Or(m.Status, greenlight.MovieDraft)
+		if _, err := stmt.ExecContext(ctx, ids[i], m.Title, m.ReleaseDate, m.Runtime, pq.Array(m.Genres), status, createdBy); err != nil {
+			return err
+		}
+	}
+	// Flush the buffered rows.
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	var extMovieIDs []int64
+	var sources, values []string
+	for i, m := range movies {
+		for src, id := range m.ExternalIDs {
+			extMovieIDs = append(extMovieIDs, ids[i])
+			sources = append(sources, src)
+			values = append(values, id)
+		}
+	}
+	if len(extMovieIDs) != 0 {
+		query = `INSERT INTO movie_external_ids (movie_id, source, value) SELECT * FROM unnest($1::bigint[], $2::text[], $3::text[])`
+		if _, err := tx.ExecContext(ctx, query, pq.Array(extMovieIDs), pq.Array(sources), pq.Array(values)); err != nil {
+			var pqErr *pq.Error
+			switch {
+			case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+				return greenlight.NewConflictError("Another movie has the same external ID.")
+			default:
+				return err
+			}
+		}
+	}
+
+	var certMovieIDs []int64
+	var countries, ratings []string
+	for i, m := range movies {
+		for country, rating := range m.Certifications {
+			certMovieIDs = append(certMovieIDs, ids[i])
+			countries = append(countries, country)
+			ratings = append(ratings, rating)
+		}
+	}
+	if len(certMovieIDs) != 0 {
+		query = `INSERT INTO movie_certifications (movie_id, country, rating) SELECT * FROM unnest($1::bigint[], $2::text[], $3::text[])`
+		if _, err := tx.ExecContext(ctx, query, pq.Array(certMovieIDs), pq.Array(countries), pq.Array(ratings)); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -166,26 +472,758 @@ func (s *MovieService) Delete(ctx context.Context, id int64) (err error) {
 	return nil
 }
 
-func (s *MovieService) Create(ctx context.Context, m *greenlight.Movie) (err error) {
-	defer multierr.Wrap(&err, "postgres.MovieService.Create")
+func (s *MovieService) Upsert(ctx context.Context, source string, movies []*greenlight.Movie) (_ greenlight.UpsertStats, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Upsert(%q)", source)
+
+	var stats greenlight.UpsertStats
 
 	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
 	defer cancel()
 
 	tx, err := s.db.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return stats, err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query := `INSERT INTO movies (title, release_date, runtime, genres) VALUES ($1, $2, $3, $4) RETURNING id, version`
-	args := []any{m.Title, m.ReleaseDate, m.Runtime, pq.Array(m.Genres)}
-	if err := tx.QueryRowContext(ctx, query, args...).Scan(&m.ID, &m.Version); err != nil {
-		return err
+	// The movies are copied to a temporary table first, since COPY can't upsert.
+	query := `
+		CREATE TEMPORARY TABLE upserted_movies (external_id text, title text, release_date date, runtime integer, genres text[], id bigint)
+		ON COMMIT DROP`
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return stats, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("upserted_movies", "external_id", "title", "release_date", "runtime", "genres"))
+	if err != nil {
+		return stats, err
+	}
+	defer stmt.Close()
+	for _, m := range movies {
+		if _, err := stmt.ExecContext(ctx, m.ExternalIDs[source], m.Title, m.ReleaseDate, m.Runtime, pq.Array(m.Genres)); err != nil {
+			return stats, err
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return stats, err
+	}
+
+	// The first of the movies having the same external ID is kept.
+	query = `DELETE FROM upserted_movies a USING upserted_movies b WHERE a.external_id = b.external_id AND a.ctid > b.ctid`
+	rs, err := tx.ExecContext(ctx, query)
+	if err != nil {
+		return stats, err
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return stats, err
+	}
+	stats.Duplicates = int(n)
+
+	// Existing movies get their IDs, and new ones get IDs allocated from the movies sequence.
+	query = `
+		UPDATE upserted_movies u SET id = COALESCE(
+			(SELECT e.movie_id FROM movie_external_ids e WHERE e.source = $1 AND e.value = u.external_id),
+			nextval(pg_get_serial_sequence('movies', 'id'))
+		)`
+	if _, err := tx.ExecContext(ctx, query, source); err != nil {
+		return stats, err
+	}
+
+	// Sources may only provide release years, so a more precise release date in the same year is kept.
+	query = `
+		UPDATE movies SET
+			title = u.title,
+			release_date = CASE
+				WHEN date_trunc('year', movies.release_date) = date_trunc('year', u.release_date) THEN movies.release_date
+				ELSE u.release_date
+			END,
+			runtime = u.runtime,
+			genres = u.genres,
+			version = movies.version+1
+		FROM upserted_movies u
+		WHERE movies.id = u.id AND (movies.title, date_trunc('year', movies.release_date), movies.runtime, movies.genres)
+			IS DISTINCT FROM (u.title, date_trunc('year', u.release_date), u.runtime, u.genres)`
+	if rs, err = tx.ExecContext(ctx, query); err != nil {
+		return stats, err
+	}
+	if n, err = rs.RowsAffected(); err != nil {
+		return stats, err
+	}
+	stats.Updated = int(n)
+
+	// The source is a trusted catalogue, so the inserted movies are published.
+	query = `
+		WITH inserted AS (
+			INSERT INTO movies (id, title, release_date, runtime, genres, status)
+			SELECT u.id, u.title, u.release_date, u.runtime, u.genres, 'published' FROM upserted_movies u
+			WHERE NOT EXISTS (SELECT 1 FROM movies WHERE movies.id = u.id)
+			RETURNING id
+		)
+		INSERT INTO movie_external_ids (movie_id, source, value)
+		SELECT u.id, $1, u.external_id FROM upserted_movies u JOIN inserted USING (id)`
+	if rs, err = tx.ExecContext(ctx, query, source); err != nil {
+		return stats, err
+	}
+	if n, err = rs.RowsAffected(); err != nil {
+		return stats, err
+	}
+	stats.Inserted = int(n)
+	stats.Unchanged = len(movies) - stats.Duplicates - stats.Inserted - stats.Updated
+
+	if err := tx.Commit(); err != nil {
+		return greenlight.UpsertStats{}, err
+	}
+	return stats, nil
+}
+
+func (s *MovieService) GetByExternalID(ctx context.Context, source, id string) (_ *greenlight.Movie, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.GetByExternalID(%q, %q)", source, id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	cols, dest := movieSelect(nil)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM movies JOIN movie_external_ids e ON e.movie_id = movies.id
+		WHERE e.source = $1 AND e.value = $2`, cols)
+	var m greenlight.Movie
+	if err := tx.QueryRowContext(ctx, query, source, id).Scan(dest(&m)...); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+	if err := localizeMovies(ctx, tx, []*greenlight.Movie{&m}); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// duplicateSimilarity is the minimum trigram similarity of the normalized titles of the duplicate movies.
+const duplicateSimilarity = 0.6
+
+// movieDuplicates returns IDs of the likely duplicates of the movie, most similar first.
+// Movies are likely duplicates if their normalized titles are similar and their release years are no more than a year apart.
+// Creation of the movies released in the year is serialized until the transaction ends, so concurrent duplicates are detected.
+func movieDuplicates(ctx context.Context, tx *sql.Tx, m *greenlight.Movie) ([]int64, error) {
+	year := m.ReleaseDate.Year()
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('movies.create'), $1)`, year); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ARRAY(
+			SELECT id FROM movies
+			WHERE normalize_title(title) % normalize_title($1)
+				AND similarity(normalize_title(title), normalize_title($1)) >= $2
+				AND release_date BETWEEN make_date($3 - 1, 1, 1) AND make_date($3 + 1, 12, 31)
+			ORDER BY similarity(normalize_title(title), normalize_title($1)) DESC, id ASC
+			LIMIT 10
+		)`
+	var ids []int64
+	if err := tx.QueryRowContext(ctx, query, m.Title, duplicateSimilarity, year).Scan(pq.Array(&ids)); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// setCertifications replaces the certifications of the movie.
+func setCertifications(ctx context.Context, tx *sql.Tx, m *greenlight.Movie) error {
+	query := `DELETE FROM movie_certifications WHERE movie_id = $1`
+	if _, err := tx.ExecContext(ctx, query, m.ID); err != nil {
+		return err
+	}
+	if len(m.Certifications) == 0 {
+		return nil
+	}
+
+	countries := make([]string, 0, len(m.Certifications))
+	ratings := make([]string, 0, len(m.Certifications))
+	for country, rating := range m.Certifications {
+		countries = append(countries, country)
+		ratings = append(ratings, rating)
+	}
+	query = `INSERT INTO movie_certifications (movie_id, country, rating) SELECT $1, * FROM unnest($2::text[], $3::text[])`
+	if _, err := tx.ExecContext(ctx, query, m.ID, pq.Array(countries), pq.Array(ratings)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// setExternalIDs replaces the external IDs of the movie.
+func setExternalIDs(ctx context.Context, tx *sql.Tx, m *greenlight.Movie) error {
+	query := `DELETE FROM movie_external_ids WHERE movie_id = $1`
+	if _, err := tx.ExecContext(ctx, query, m.ID); err != nil {
 		return err
 	}
+	if len(m.ExternalIDs) == 0 {
+		return nil
+	}
+
+	sources := make([]string, 0, len(m.ExternalIDs))
+	values := make([]string, 0, len(m.ExternalIDs))
+	for src, id := range m.ExternalIDs {
+		sources = append(sources, src)
+		values = append(values, id)
+	}
+	query = `INSERT INTO movie_external_ids (movie_id, source, value) SELECT $1, * FROM unnest($2::text[], $3::text[])`
+	if _, err := tx.ExecContext(ctx, query, m.ID, pq.Array(sources), pq.Array(values)); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("Another movie has the same external ID.")
+		default:
+			return err
+		}
+	}
 	return nil
 }
+
+func (s *MovieService) Delete(ctx context.Context, id int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Delete(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `DELETE FROM movies where id = $1`
+	args := []any{id}
+	rs, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *MovieService) Create(ctx context.Context, m *greenlight.Movie) (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Create")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := validGenres(ctx, tx, m.Genres); err != nil {
+		return err
+	}
+
+	mode := greenlight.DuplicateModeFromContext(ctx)
+	if mode != greenlight.DuplicatesOff {
+		dups, err := movieDuplicates(ctx, tx, m)
+		if err != nil {
+			return err
+		}
+		if len(dups) != 0 && mode == greenlight.DuplicatesStrict {
+			err := greenlight.NewConflictError("The movie is likely a duplicate of existing movies.")
+			err.IDs = dups
+			return err
+		}
+		m.Duplicates = dups
+	}
+
+	m.CreatedBy = greenlight.UserIDFromContext(ctx)
+	if m.Status == "" {
+		m.Status = greenlight.MovieDraft
+	}
+	query := `
+		INSERT INTO movies (title, release_date, runtime, genres, poster, created_by, status, publish_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8)
+		RETURNING id, version`
+	args := []any{m.Title, m.ReleaseDate, m.Runtime, pq.Array(m.Genres), jsonb{m.Poster}, m.CreatedBy, m.Status, nullTime(m.PublishAt)}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&m.ID, &m.Version); err != nil {
+		return err
+	}
+	if err := setExternalIDs(ctx, tx, m); err != nil {
+		return err
+	}
+	if err := setCertifications(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *MovieService) Translations(ctx context.Context, id int64) (_ []*greenlight.MovieTranslation, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Translations(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, greenlight.ErrNotFound
+	}
+
+	query := `SELECT language, title, synopsis FROM movie_translations WHERE movie_id = $1 ORDER BY language ASC`
+	rs, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var translations []*greenlight.MovieTranslation
+	for rs.Next() {
+		var t greenlight.MovieTranslation
+		if err := rs.Scan(&t.Language, &t.Title, &t.Synopsis); err != nil {
+			return nil, err
+		}
+		translations = append(translations, &t)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (s *MovieService) SetTranslation(ctx context.Context, id int64, t *greenlight.MovieTranslation) (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.SetTranslation(%d, %q)", id, t.Language)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO movie_translations (movie_id, language, title, synopsis) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (movie_id, language) DO UPDATE SET (title, synopsis) = (EXCLUDED.title, EXCLUDED.synopsis)`
+	if _, err := tx.ExecContext(ctx, query, id, t.Language, t.Title, t.Synopsis); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation":
+			return greenlight.ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *MovieService) DeleteTranslation(ctx context.Context, id int64, lang string) (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.DeleteTranslation(%d, %q)", id, lang)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `DELETE FROM movie_translations WHERE movie_id = $1 AND language = $2`
+	rs, err := tx.ExecContext(ctx, query, id, lang)
+	if err != nil {
+		return err
+	}
+
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// localizeMovies replaces the titles of the movies with the best matching translations
+// to the languages of the context. Movies without a matching translation keep the original title.
+func localizeMovies(ctx context.Context, tx *sql.Tx, movies []*greenlight.Movie) error {
+	langs := greenlight.LanguageFallbacks(greenlight.LanguagesFromContext(ctx))
+	if len(langs) == 0 || len(movies) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*greenlight.Movie, len(movies))
+	ids := make([]int64, len(movies))
+	for i, m := range movies {
+		byID[m.ID] = m
+		ids[i] = m.ID
+	}
+
+	query := `
+		SELECT DISTINCT ON (movie_id) movie_id, language, title, synopsis
+		FROM movie_translations
+		WHERE movie_id = ANY($1) AND language = ANY($2)
+		ORDER BY movie_id, array_position($2, language)`
+	rs, err := tx.QueryContext(ctx, query, pq.Array(ids), pq.Array(langs))
+	if err != nil {
+		return err
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		var id int64
+		var t greenlight.MovieTranslation
+		if err := rs.Scan(&id, &t.Language, &t.Title, &t.Synopsis); err != nil {
+			return err
+		}
+		if m, ok := byID[id]; ok {
+			m.Title, m.Synopsis, m.Language = t.Title, t.Synopsis, t.Language
+		}
+	}
+	return rs.Err()
+}
+
+// validGenres returns an error if some of the movie genres don't exist.
+func validGenres(ctx context.Context, tx *sql.Tx, genres []string) error {
+	unknown, err := unknownGenres(ctx, tx, genres)
+	if err != nil {
+		return err
+	}
+	if len(unknown) != 0 {
+		err := greenlight.NewInvalidError("Movie is invalid.")
+		err.AddViolationMsg("genres", fmt.Sprintf("Unknown genre(s): %s.", strings.Join(unknown, ", ")))
+		return err
+	}
+	return nil
+}
+
+// movieWhere returns the condition selecting movies matching the filter and its positional arguments.
+func movieWhere(filter greenlight.MovieFilter) (where string, args []any) {
+	conds := []string{"TRUE"}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Title != "" {
+		title := arg(filter.Title)
+		conds = append(conds, fmt.Sprintf(`(LOWER(title) = LOWER(%[1]s) OR EXISTS (
+			SELECT 1 FROM movie_translations t WHERE t.movie_id = movies.id AND LOWER(t.title) = LOWER(%[1]s)))`, title))
+	}
+	if len(filter.Genres) != 0 {
+		op := "@>"
+		if filter.GenresMode == "any" {
+			op = "&&"
+		}
+		conds = append(conds, fmt.Sprintf("genres %s %s", op, arg(pq.Array(filter.Genres))))
+	}
+	if len(filter.Tags) != 0 {
+		conds = append(conds, fmt.Sprintf("tags @> %s", arg(pq.Array(filter.Tags))))
+	}
+	if !filter.ReleaseDateFrom.IsZero() {
+		conds = append(conds, fmt.Sprintf("release_date >= %s", arg(filter.ReleaseDateFrom)))
+	}
+	if !filter.ReleaseDateTo.IsZero() {
+		conds = append(conds, fmt.Sprintf("release_date <= %s", arg(filter.ReleaseDateTo)))
+	}
+	if filter.Year != 0 {
+		from := time.Date(filter.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		conds = append(conds, fmt.Sprintf("release_date >= %s AND release_date < %s", arg(from), arg(from.AddDate(1, 0, 0))))
+	}
+	if filter.RuntimeMin != 0 {
+		conds = append(conds, fmt.Sprintf("runtime >= %s", arg(filter.RuntimeMin)))
+	}
+	if filter.RuntimeMax != 0 {
+		conds = append(conds, fmt.Sprintf("runtime <= %s", arg(filter.RuntimeMax)))
+	}
+	if filter.CreatedBy != 0 {
+		conds = append(conds, fmt.Sprintf("created_by = %s", arg(filter.CreatedBy)))
+	}
+	if filter.PersonID != 0 {
+		conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM credits WHERE credits.movie_id = movies.id AND credits.person_id = %s)", arg(filter.PersonID)))
+	}
+	if filter.Status != "" {
+		conds = append(conds, fmt.Sprintf("status = %s", arg(filter.Status)))
+	}
+	if c := filter.Certification; c != (greenlight.Certification{}) {
+		conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM movie_certifications c WHERE c.movie_id = movies.id AND c.country = %s AND c.rating = %s)", arg(c.Country), arg(c.Rating)))
+	}
+	if filter.MaxCertification != (greenlight.Certification{}) {
+		conds = append(conds, certificationAllows(filter.MaxCertification, &args))
+	}
+	if filter.VisibleTo != nil {
+		conds = append(conds, movieVisible(filter.VisibleTo, &args))
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// movieVisible returns the condition matching the movies visible to the user, see [greenlight.Movie.VisibleTo].
+// A nil user matches any movie. The arguments of the condition are appended to the args.
+func movieVisible(u *greenlight.User, args *[]any) string {
+	conds := []string{"TRUE"}
+	if u == nil {
+		return conds[0]
+	}
+	if !u.Role.Grants(greenlight.RoleEditor) {
+		*args = append(*args, u.ID)
+		conds = append(conds, fmt.Sprintf("((movies.status = 'published' AND (movies.publish_at IS NULL OR movies.publish_at <= NOW())) OR movies.created_by = $%d)", len(*args)))
+	}
+	if u.MaxCertification != (greenlight.Certification{}) {
+		conds = append(conds, certificationAllows(u.MaxCertification, args))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// certificationAllows returns the condition matching the movies allowed by the maximum certification,
+// see [greenlight.Certification.Allows]. The arguments of the condition are appended to the args.
+func certificationAllows(c greenlight.Certification, args *[]any) string {
+	system := greenlight.CertificationSystems[c.Country]
+	*args = append(*args, c.Country, pq.Array(system), slices.Index(system, c.Rating)+1)
+	n := len(*args)
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM movie_certifications c
+		WHERE c.movie_id = movies.id AND c.country = $%d AND array_position($%d::text[], c.rating) <= $%d)`, n-2, n-1, n)
+}
+
+// movieColumns maps the [greenlight.Movie] fields to the columns they are selected from.
+// Columns are qualified with the table name, so they can be selected when the movies table is joined.
+var movieColumns = []struct {
+	field  string
+	column string
+	dest   func(m *greenlight.Movie) any
+}{
+	{"id", "movies.id", func(m *greenlight.Movie) any { return &m.ID }},
+	{"title", "movies.title", func(m *greenlight.Movie) any { return &m.Title }},
+	{"release_date", "movies.release_date", func(m *greenlight.Movie) any { return &m.ReleaseDate }},
+	{"runtime", "movies.runtime", func(m *greenlight.Movie) any { return &m.Runtime }},
+	{"genres", "movies.genres", func(m *greenlight.Movie) any { return pq.Array(&m.Genres) }},
+	{"poster", "movies.poster", func(m *greenlight.Movie) any { return jsonb{&m.Poster} }},
+	{"rating", "movies.rating", func(m *greenlight.Movie) any { return &m.Rating }},
+	{"votes", "movies.votes", func(m *greenlight.Movie) any { return &m.Votes }},
+	{"external_ids", "(SELECT jsonb_object_agg(e.source, e.value) FROM movie_external_ids e WHERE e.movie_id = movies.id)", func(m *greenlight.Movie) any { return jsonb{&m.ExternalIDs} }},
+	{"certifications", "(SELECT jsonb_object_agg(c.country, c.rating) FROM movie_certifications c WHERE c.movie_id = movies.id)", func(m *greenlight.Movie) any { return jsonb{&m.Certifications} }},
+	{"tags", "movies.tags", func(m *greenlight.Movie) any { return pq.Array(&m.Tags) }},
+	{"created_by", "COALESCE(movies.created_by, 0)", func(m *greenlight.Movie) any { return &m.CreatedBy }},
+	{"status", "movies.status", func(m *greenlight.Movie) any { return &m.Status }},
+	{"publish_at", "movies.publish_at", func(m *greenlight.Movie) any { return zeroTime{&m.PublishAt} }},
+	{"version", "movies.version", func(m *greenlight.Movie) any { return &m.Version }},
+}
+
+// movieSelect returns the select list for the given fields and a function returning the scan destinations of a movie.
+// The "id" and "version" fields are always selected. If no fields are given, all fields are selected.
+func movieSelect(fields []string) (cols string, dest func(m *greenlight.Movie) []any) {
+	var names []string
+	var dests []func(m *greenlight.Movie) any
+	for _, c := range movieColumns {
+		if len(fields) == 0 || c.field == "id" || c.field == "version" || slices.Contains(fields, c.field) {
+			names = append(names, c.column)
+			dests = append(dests, c.dest)
+		}
+	}
+
+	dest = func(m *greenlight.Movie) []any {
+		ds := make([]any, len(dests))
+		for i, d := range dests {
+			ds[i] = d(m)
+		}
+		return ds
+	}
+	return strings.Join(names, ", "), dest
+}
+
+func (s *MovieService) Stats(ctx context.Context, visibleTo *greenlight.User) (_ *greenlight.MovieStats, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Stats")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT total, by_status, by_genre, by_decade, runtime_avg, runtime_median, recent_ids, refreshed_at FROM movie_stats`
+	var stats greenlight.MovieStats
+	var recent []int64
+	dest := []any{
+		&stats.Total, jsonb{&stats.ByStatus}, jsonb{&stats.ByGenre}, jsonb{&stats.ByDecade},
+		&stats.RuntimeAvg, &stats.RuntimeMedian, (*pq.Int64Array)(&recent), &stats.RefreshedAt,
+	}
+	if err := tx.QueryRowContext(ctx, query).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	// The recent movies are selected by their IDs, so their details and visibility are up to date.
+	cols, movieDest := movieSelect(nil)
+	args := []any{pq.Array(recent)}
+	query = fmt.Sprintf(`SELECT %s FROM movies WHERE id = ANY($1) AND %s ORDER BY id DESC`, cols, movieVisible(visibleTo, &args))
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	stats.Recent = []*greenlight.Movie{}
+	for rs.Next() {
+		var m greenlight.Movie
+		if err := rs.Scan(movieDest(&m)...); err != nil {
+			return nil, err
+		}
+		stats.Recent = append(stats.Recent, &m)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+	if err := localizeMovies(ctx, tx, stats.Recent); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (s *MovieService) RefreshStats(ctx context.Context) (err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.RefreshStats")
+
+	// Refreshing scans all the movies, so it isn't limited by the query timeout.
+	// Concurrent refreshing doesn't block reading the statistics.
+	if _, err := s.db.db.ExecContext(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY movie_stats`); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *MovieService) Changes(ctx context.Context, filter greenlight.MovieChangeFilter) (_ []*greenlight.MovieChange, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.Changes(%d)", filter.Since)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		SELECT seq, movie_id, op, version, changed_at
+		FROM movie_changes
+		WHERE seq > $1
+		ORDER BY seq ASC
+		LIMIT $2`
+	rs, err := tx.QueryContext(ctx, query, filter.Since, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	changes := []*greenlight.MovieChange{}
+	for rs.Next() {
+		var c greenlight.MovieChange
+		if err := rs.Scan(&c.Seq, &c.MovieID, &c.Op, &c.Version, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &c)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SequenceChanges is called by a single caller at a time, the concurrent callers return immediately.
+// Since the caller only sees the committed changes, and a call starts after the previous one is committed,
+// the changes are sequenced in the commit order without serializing the transactions making them.
+func (s *MovieService) SequenceChanges(ctx context.Context) (_ int, err error) {
+	defer multierr.Wrap(&err, "postgres.MovieService.SequenceChanges")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock('movie_change_log'::regclass::oid::bigint)`).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	// The changes of a transaction are appended in the order they were made.
+	query := `
+		WITH sequenced AS (
+			DELETE FROM movie_change_log RETURNING id, movie_id, op, version, changed_at
+		)
+		INSERT INTO movie_changes (movie_id, op, version, changed_at)
+		SELECT movie_id, op, version, changed_at FROM sequenced ORDER BY id`
+	res, err := tx.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
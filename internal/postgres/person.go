@@ -0,0 +1,336 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+	"github.com/lib/pq"
+)
+
+// PersonService represents a service for managing people backed by PostgreSQL.
+type PersonService struct {
+	db *DB
+}
+
+var _ greenlight.PersonService = (*PersonService)(nil)
+
+// NewPersonService returns a new instance of [PersonService].
+func NewPersonService(db *DB) *PersonService {
+	return &PersonService{
+		db: db,
+	}
+}
+
+func (s *PersonService) Get(ctx context.Context, id int64) (_ *greenlight.Person, err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.Get(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT id, name, birth_date, biography, version FROM people WHERE id = $1`
+	args := []any{id}
+	var p greenlight.Person
+	var birthDate sql.NullTime
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&p.ID, &p.Name, &birthDate, &p.Biography, &p.Version); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+	p.BirthDate = birthDate.Time
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *PersonService) GetAll(ctx context.Context, filter greenlight.PersonFilter) (_ []*greenlight.Person, err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.GetAll")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sortCol, sortDir := filter.Sort, "ASC"
+	if v, ok := strings.CutPrefix(sortCol, "-"); ok {
+		sortCol = v
+		sortDir = "DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, name, birth_date, biography, version
+		FROM people
+		WHERE (name ILIKE '%%' || $1 || '%%' OR $1 = '')
+		ORDER BY %s %s, id ASC
+		LIMIT $2 OFFSET $3`, sortCol, sortDir)
+	rs, err := tx.QueryContext(ctx, query, filter.Name, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var people []*greenlight.Person
+	for rs.Next() {
+		var p greenlight.Person
+		var birthDate sql.NullTime
+		if err := rs.Scan(&p.ID, &p.Name, &birthDate, &p.Biography, &p.Version); err != nil {
+			return nil, err
+		}
+		p.BirthDate = birthDate.Time
+		people = append(people, &p)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return people, nil
+}
+
+func (s *PersonService) Create(ctx context.Context, p *greenlight.Person) (err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.Create")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO people (name, birth_date, biography) VALUES ($1, $2, $3) RETURNING id, version`
+	args := []any{p.Name, nullTime(p.BirthDate), p.Biography}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&p.ID, &p.Version); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PersonService) Update(ctx context.Context, p *greenlight.Person) (err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.Update(%d)", p.ID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `UPDATE people SET (name, birth_date, biography, version) = ($1, $2, $3, version+1) WHERE id = $4 AND version = $5 RETURNING version`
+	args := []any{p.Name, nullTime(p.BirthDate), p.Biography, p.ID, p.Version}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&p.Version); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.NewConflictError("Conflicting change")
+		default:
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PersonService) Delete(ctx context.Context, id int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.Delete(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `DELETE FROM people WHERE id = $1`
+	args := []any{id}
+	rs, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PersonService) MovieCredits(ctx context.Context, movieIDs ...int64) (_ []*greenlight.Credit, err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.MovieCredits")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		SELECT c.movie_id, c.person_id, c.role, c.character, c.billing, p.id, p.name, p.birth_date, p.biography, p.version
+		FROM credits c
+		JOIN people p ON p.id = c.person_id
+		WHERE c.movie_id = ANY($1)
+		ORDER BY c.movie_id, c.role, c.billing`
+	rs, err := tx.QueryContext(ctx, query, pq.Array(movieIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var credits []*greenlight.Credit
+	for rs.Next() {
+		c := greenlight.Credit{Person: &greenlight.Person{}}
+		var birthDate sql.NullTime
+		if err := rs.Scan(&c.MovieID, &c.PersonID, &c.Role, &c.Character, &c.Billing,
+			&c.Person.ID, &c.Person.Name, &birthDate, &c.Person.Biography, &c.Person.Version); err != nil {
+			return nil, err
+		}
+		c.Person.BirthDate = birthDate.Time
+		credits = append(credits, &c)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+func (s *PersonService) SetMovieCredits(ctx context.Context, movieID int64, credits []*greenlight.Credit) (err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.SetMovieCredits(%d)", movieID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Lock the movie, so concurrent updates of the credits are serialized.
+	if err := lockMovie(ctx, tx, movieID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM credits WHERE movie_id = $1`, movieID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO credits (movie_id, person_id, role, character, billing) VALUES ($1, $2, $3, $4, $5)`
+	for _, c := range credits {
+		c.MovieID = movieID
+		if _, err := tx.ExecContext(ctx, query, c.MovieID, c.PersonID, c.Role, c.Character, c.Billing); err != nil {
+			var pqErr *pq.Error
+			switch {
+			case errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation":
+				return greenlight.NewInvalidError("Person %d doesn't exist.", c.PersonID)
+			case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+				return greenlight.NewConflictError("Person %d is credited more than once with the same role and character.", c.PersonID)
+			default:
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PersonService) Filmography(ctx context.Context, personID int64, visibleTo *greenlight.User) (_ []*greenlight.Credit, err error) {
+	defer multierr.Wrap(&err, "postgres.PersonService.Filmography(%d)", personID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM people WHERE id = $1)`, personID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, greenlight.ErrNotFound
+	}
+
+	cols, dest := movieSelect(nil)
+	args := []any{personID}
+	query := fmt.Sprintf(`
+		SELECT c.movie_id, c.person_id, c.role, c.character, c.billing, %s
+		FROM credits c
+		JOIN movies ON movies.id = c.movie_id
+		WHERE c.person_id = $1 AND %s
+		ORDER BY movies.release_date DESC, movies.id, c.role, c.billing`, cols, movieVisible(visibleTo, &args))
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var credits []*greenlight.Credit
+	for rs.Next() {
+		c := greenlight.Credit{Movie: &greenlight.Movie{}}
+		args := append([]any{&c.MovieID, &c.PersonID, &c.Role, &c.Character, &c.Billing}, dest(c.Movie)...)
+		if err := rs.Scan(args...); err != nil {
+			return nil, err
+		}
+		credits = append(credits, &c)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
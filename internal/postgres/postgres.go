@@ -6,6 +6,7 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/denpeshkov/greenlight/internal/multierr"
 )
@@ -68,7 +69,10 @@ func (db *DB) Open() (err error) {
 func (db *DB) Close() (err error) {
 	defer multierr.Wrap(&err, "postgres.DB.Close")
 
-	err1 := db.Migrate(DOWN)
+	var err1 error
+	if !db.opts.keepOnClose {
+		err1 = db.Migrate(DOWN)
+	}
 	err2 := db.db.Close()
 	if err := multierr.Join(err2, err1); err != nil {
 		return err
@@ -80,6 +84,26 @@ func (db *DB) Stats() sql.DBStats {
 	return db.db.Stats()
 }
 
+// nullTime returns a NULL for the zero time.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// zeroTime is a scan destination of a nullable time, setting the time to the zero time for a NULL.
+type zeroTime struct {
+	t *time.Time
+}
+
+// Scan implements the [sql.Scanner] interface.
+func (z zeroTime) Scan(src any) error {
+	var t sql.NullTime
+	if err := t.Scan(src); err != nil {
+		return err
+	}
+	*z.t = t.Time
+	return nil
+}
+
 // newLogger returns a database logger.
 func newLogger() *slog.Logger {
 	opts := slog.HandlerOptions{Level: slog.LevelDebug}
@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+	"github.com/lib/pq"
+)
+
+// ReviewService represents a service for managing reviews backed by PostgreSQL.
+type ReviewService struct {
+	db *DB
+}
+
+var _ greenlight.ReviewService = (*ReviewService)(nil)
+
+// NewReviewService returns a new instance of [ReviewService].
+func NewReviewService(db *DB) *ReviewService {
+	return &ReviewService{
+		db: db,
+	}
+}
+
+func (s *ReviewService) Get(ctx context.Context, id int64) (_ *greenlight.Review, err error) {
+	defer multierr.Wrap(&err, "postgres.ReviewService.Get(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT id, movie_id, user_id, rating, text, created_at, updated_at, version FROM reviews WHERE id = $1`
+	args := []any{id}
+	var r greenlight.Review
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&r.ID, &r.MovieID, &r.UserID, &r.Rating, &r.Text, &r.CreatedAt, &r.UpdatedAt, &r.Version); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *ReviewService) GetAll(ctx context.Context, filter greenlight.ReviewFilter) (_ []*greenlight.Review, err error) {
+	defer multierr.Wrap(&err, "postgres.ReviewService.GetAll")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	sortCol, sortDir := filter.Sort, "ASC"
+	if v, ok := strings.CutPrefix(sortCol, "-"); ok {
+		sortCol = v
+		sortDir = "DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, movie_id, user_id, rating, text, created_at, updated_at, version
+		FROM reviews
+		WHERE movie_id = $1
+		ORDER BY %s %s, id ASC
+		LIMIT $2 OFFSET $3`, sortCol, sortDir)
+	rs, err := tx.QueryContext(ctx, query, filter.MovieID, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var reviews []*greenlight.Review
+	for rs.Next() {
+		var r greenlight.Review
+		if err := rs.Scan(&r.ID, &r.MovieID, &r.UserID, &r.Rating, &r.Text, &r.CreatedAt, &r.UpdatedAt, &r.Version); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &r)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (s *ReviewService) Create(ctx context.Context, r *greenlight.Review) (err error) {
+	defer multierr.Wrap(&err, "postgres.ReviewService.Create")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockMovie(ctx, tx, r.MovieID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO reviews (movie_id, user_id, rating, text) VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at, version`
+	args := []any{r.MovieID, r.UserID, r.Rating, r.Text}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&r.ID, &r.CreatedAt, &r.UpdatedAt, &r.Version); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+			return greenlight.NewConflictError("You have already reviewed this movie.")
+		default:
+			return err
+		}
+	}
+
+	if err := updateMovieRating(ctx, tx, r.MovieID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ReviewService) Update(ctx context.Context, r *greenlight.Review) (err error) {
+	defer multierr.Wrap(&err, "postgres.ReviewService.Update(%d)", r.ID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockMovie(ctx, tx, r.MovieID); err != nil {
+		return err
+	}
+
+	query := `UPDATE reviews SET (rating, text, updated_at, version) = ($1, $2, NOW(), version+1) WHERE id = $3 AND version = $4 RETURNING updated_at, version`
+	args := []any{r.Rating, r.Text, r.ID, r.Version}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&r.UpdatedAt, &r.Version); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.NewConflictError("Conflicting change")
+		default:
+			return err
+		}
+	}
+
+	if err := updateMovieRating(ctx, tx, r.MovieID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ReviewService) Delete(ctx context.Context, id int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.ReviewService.Delete(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var movieID int64
+	if err := tx.QueryRowContext(ctx, `SELECT movie_id FROM reviews WHERE id = $1`, id).Scan(&movieID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.ErrNotFound
+		default:
+			return err
+		}
+	}
+	if err := lockMovie(ctx, tx, movieID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reviews WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	if err := updateMovieRating(ctx, tx, movieID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// lockMovie locks the movie row until the end of the transaction.
+// It serializes concurrent changes of the movie aggregates.
+func lockMovie(ctx context.Context, tx *sql.Tx, id int64) error {
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM movies WHERE id = $1 FOR UPDATE`, id).Scan(&id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.ErrNotFound
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// updateMovieRating recalculates the rating and the number of votes of the movie from its reviews.
+// The movie version isn't changed, since the rating is not editable.
+func updateMovieRating(ctx context.Context, tx *sql.Tx, movieID int64) error {
+	query := `
+		UPDATE movies SET (rating, votes) = (
+			SELECT COALESCE(AVG(rating), 0), COUNT(*) FROM reviews WHERE movie_id = $1
+		)
+		WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, movieID)
+	return err
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+// TagService represents a service for managing movie tags backed by PostgreSQL.
+type TagService struct {
+	db *DB
+}
+
+var _ greenlight.TagService = (*TagService)(nil)
+
+// NewTagService returns a new instance of [TagService].
+func NewTagService(db *DB) *TagService {
+	return &TagService{
+		db: db,
+	}
+}
+
+func (s *TagService) MovieTags(ctx context.Context, movieID int64) (_ []*greenlight.MovieTag, err error) {
+	defer multierr.Wrap(&err, "postgres.TagService.MovieTags(%d)", movieID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1)`, movieID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, greenlight.ErrNotFound
+	}
+
+	query := `
+		SELECT tag, COUNT(*), bool_or(user_id = $2)
+		FROM movie_tags
+		WHERE movie_id = $1
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag ASC`
+	rs, err := tx.QueryContext(ctx, query, movieID, greenlight.UserIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	tags := []*greenlight.MovieTag{}
+	for rs.Next() {
+		var t greenlight.MovieTag
+		if err := rs.Scan(&t.Tag, &t.Votes, &t.Voted); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &t)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (s *TagService) AddTag(ctx context.Context, movieID int64, tag string) (err error) {
+	defer multierr.Wrap(&err, "postgres.TagService.AddTag(%d, %q)", movieID, tag)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockMovie(ctx, tx, movieID); err != nil {
+		return err
+	}
+
+	// Adding the tag again is a no-op, since the user can vote only once.
+	query := `INSERT INTO movie_tags (movie_id, tag, user_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, movieID, tag, greenlight.UserIDFromContext(ctx)); err != nil {
+		return err
+	}
+	if err := updateMovieTags(ctx, tx, movieID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *TagService) RemoveTag(ctx context.Context, movieID int64, tag string, all bool) (err error) {
+	defer multierr.Wrap(&err, "postgres.TagService.RemoveTag(%d, %q)", movieID, tag)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockMovie(ctx, tx, movieID); err != nil {
+		return err
+	}
+
+	query := `DELETE FROM movie_tags WHERE movie_id = $1 AND tag = $2 AND ($3 OR user_id = $4)`
+	rs, err := tx.ExecContext(ctx, query, movieID, tag, all, greenlight.UserIDFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+	if err := updateMovieTags(ctx, tx, movieID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *TagService) Popular(ctx context.Context, filter greenlight.TagFilter) (_ []*greenlight.TagCount, err error) {
+	defer multierr.Wrap(&err, "postgres.TagService.Popular")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	prefix := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(filter.Prefix)
+	query := `
+		SELECT tag, COUNT(DISTINCT movie_id), COUNT(*)
+		FROM movie_tags
+		WHERE tag LIKE $1 || '%'
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag ASC
+		LIMIT $2 OFFSET $3`
+	args := []any{prefix, filter.PageSize, (filter.Page - 1) * filter.PageSize}
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	tags := []*greenlight.TagCount{}
+	for rs.Next() {
+		var t greenlight.TagCount
+		if err := rs.Scan(&t.Tag, &t.Movies, &t.Votes); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &t)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// updateMovieTags recalculates the distinct tags of the movie used to filter the movies by tags.
+// The movie version isn't changed, since the tags are not editable as a movie field.
+func updateMovieTags(ctx context.Context, tx *sql.Tx, movieID int64) error {
+	query := `
+		UPDATE movies SET tags = ARRAY(
+			SELECT DISTINCT tag FROM movie_tags WHERE movie_id = $1 ORDER BY tag
+		)
+		WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, movieID)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/denpeshkov/greenlight/internal/greenlight"
 	"github.com/denpeshkov/greenlight/internal/multierr"
@@ -23,6 +24,27 @@ func NewUserService(db *DB) *UserService {
 	}
 }
 
+// certification is a scan destination of a certification in the "COUNTRY:RATING" format.
+// An empty string is scanned as the zero certification.
+type certification struct {
+	c *greenlight.Certification
+}
+
+// Scan implements the [sql.Scanner] interface.
+func (c certification) Scan(src any) error {
+	var s sql.NullString
+	if err := s.Scan(src); err != nil {
+		return err
+	}
+	if s.String == "" {
+		*c.c = greenlight.Certification{}
+		return nil
+	}
+	country, rating, _ := strings.Cut(s.String, ":")
+	*c.c = greenlight.Certification{Country: country, Rating: rating}
+	return nil
+}
+
 func (s *UserService) Get(ctx context.Context, email string) (_ *greenlight.User, err error) {
 	defer multierr.Wrap(&err, "postgres.UserService.Get")
 
@@ -35,10 +57,40 @@ func (s *UserService) Get(ctx context.Context, email string) (_ *greenlight.User
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query := `SELECT id, name, email, password_hash, version FROM users WHERE email = $1`
+	query := `SELECT id, name, email, password_hash, role, COALESCE(max_certification, ''), version FROM users WHERE email = $1`
 	args := []any{email}
 	var u greenlight.User
-	if err := tx.QueryRowContext(ctx, query, args...).Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Version); err != nil {
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, certification{&u.MaxCertification}, &u.Version); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (s *UserService) GetByID(ctx context.Context, id int64) (_ *greenlight.User, err error) {
+	defer multierr.Wrap(&err, "postgres.UserService.GetByID(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT id, name, email, password_hash, role, COALESCE(max_certification, ''), version FROM users WHERE id = $1`
+	args := []any{id}
+	var u greenlight.User
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, certification{&u.MaxCertification}, &u.Version); err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return nil, greenlight.ErrNotFound
@@ -94,10 +146,15 @@ func (s *UserService) Update(ctx context.Context, u *greenlight.User) (err error
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query := `UPDATE users SET (name, email, password_hash, version) = ($1, $2, $3, version+1) WHERE id = $5 AND version = $6 RETURNING version`
-	args := []any{u.Name, u.Email, u.Password, u.ID, u.Version}
-	if err := tx.QueryRowContext(ctx, query, args...).Scan(&u.ID, &u.Version); err != nil {
+	query := `
+		UPDATE users SET (name, email, password_hash, max_certification, version) = ($1, $2, $3, NULLIF($4, ''), version+1)
+		WHERE id = $5 AND version = $6
+		RETURNING version`
+	args := []any{u.Name, u.Email, u.Password, u.MaxCertification.String(), u.ID, u.Version}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&u.Version); err != nil {
 		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.NewConflictError("Conflicting change")
 		case err.Error() == `pq: duplicate key value violates unique constraint "users_email_key"`:
 			return greenlight.NewConflictError("A user with this email already exists.")
 		default:
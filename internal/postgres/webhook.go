@@ -0,0 +1,385 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+	"github.com/lib/pq"
+)
+
+// WebhookService represents a service for managing webhooks backed by PostgreSQL.
+type WebhookService struct {
+	db *DB
+}
+
+var _ greenlight.WebhookService = (*WebhookService)(nil)
+
+// NewWebhookService returns a new instance of [WebhookService].
+func NewWebhookService(db *DB) *WebhookService {
+	return &WebhookService{
+		db: db,
+	}
+}
+
+const webhookColumns = `w.id, w.user_id, w.url, w.events, w.secret, w.active, w.failures, w.created_at, w.version`
+
+// webhookDest returns the scan destinations of the webhookColumns, and a function converting the scanned events.
+func webhookDest(w *greenlight.Webhook) (dest []any, done func()) {
+	var events []string
+	dest = []any{&w.ID, &w.UserID, &w.URL, pq.Array(&events), &w.Secret, &w.Active, &w.Failures, &w.CreatedAt, &w.Version}
+	return dest, func() {
+		w.Events = make([]greenlight.MovieChangeOp, len(events))
+		for i, e := range events {
+			w.Events[i] = greenlight.MovieChangeOp(e)
+		}
+	}
+}
+
+// webhookEvents returns the events of the webhook as a PostgreSQL array.
+func webhookEvents(w *greenlight.Webhook) any {
+	events := make([]string, len(w.Events))
+	for i, e := range w.Events {
+		events[i] = string(e)
+	}
+	return pq.Array(events)
+}
+
+func (s *WebhookService) Get(ctx context.Context, id int64) (_ *greenlight.Webhook, err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.Get(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT ` + webhookColumns + ` FROM webhooks w WHERE w.id = $1`
+	var w greenlight.Webhook
+	dest, done := webhookDest(&w)
+	if err := tx.QueryRowContext(ctx, query, id).Scan(dest...); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, greenlight.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+	done()
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (s *WebhookService) GetAll(ctx context.Context, userID int64) (_ []*greenlight.Webhook, err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.GetAll(%d)", userID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `SELECT ` + webhookColumns + ` FROM webhooks w WHERE w.user_id = $1 ORDER BY w.id ASC`
+	rs, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	webhooks := []*greenlight.Webhook{}
+	for rs.Next() {
+		var w greenlight.Webhook
+		dest, done := webhookDest(&w)
+		if err := rs.Scan(dest...); err != nil {
+			return nil, err
+		}
+		done()
+		webhooks = append(webhooks, &w)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) Create(ctx context.Context, w *greenlight.Webhook) (err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.Create")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO webhooks (user_id, url, events, secret, active) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, version`
+	args := []any{w.UserID, w.URL, webhookEvents(w), w.Secret, w.Active}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&w.ID, &w.CreatedAt, &w.Version); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, w *greenlight.Webhook) (err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.Update(%d)", w.ID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		UPDATE webhooks SET (url, events, secret, active, failures, version) = ($1, $2, $3, $4, $5, version+1)
+		WHERE id = $6 AND version = $7
+		RETURNING version`
+	args := []any{w.URL, webhookEvents(w), w.Secret, w.Active, w.Failures, w.ID, w.Version}
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&w.Version); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return greenlight.NewConflictError("Conflicting change")
+		default:
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.Delete(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rs, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return greenlight.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) Disable(ctx context.Context, id int64) (err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.Disable(%d)", id)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `UPDATE webhooks SET (active, version) = (FALSE, version+1) WHERE id = $1 AND active`
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deliveryColumns are the columns of a delivery joined with its change.
+const deliveryColumns = `
+	d.id, d.webhook_id, d.status, d.attempts, d.next_attempt_at, COALESCE(d.status_code, 0), COALESCE(d.error, ''),
+	d.last_attempt_at, d.created_at, c.seq, c.movie_id, c.op, c.version, c.changed_at`
+
+func deliveryDest(d *greenlight.WebhookDelivery) []any {
+	d.Change = &greenlight.MovieChange{}
+	return []any{
+		&d.ID, &d.WebhookID, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.StatusCode, &d.Error,
+		zeroTime{&d.LastAttemptAt}, &d.CreatedAt, &d.Change.Seq, &d.Change.MovieID, &d.Change.Op, &d.Change.Version, &d.Change.ChangedAt,
+	}
+}
+
+func (s *WebhookService) Deliveries(ctx context.Context, filter greenlight.WebhookDeliveryFilter) (_ []*greenlight.WebhookDelivery, err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.Deliveries(%d)", filter.WebhookID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d JOIN movie_changes c ON c.seq = d.change_seq
+		WHERE d.webhook_id = $1 AND ($2::text = '' OR d.status = $2)
+		ORDER BY d.id DESC
+		LIMIT $3 OFFSET $4`
+	args := []any{filter.WebhookID, filter.Status, filter.PageSize, (filter.Page - 1) * filter.PageSize}
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	deliveries := []*greenlight.WebhookDelivery{}
+	for rs.Next() {
+		var d greenlight.WebhookDelivery
+		if err := rs.Scan(deliveryDest(&d)...); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookService) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) (_ []*greenlight.WebhookDelivery, err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.ClaimDeliveries")
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The claimed deliveries are postponed for the lease, so that other instances skip them while they're attempted.
+	// If the attempt isn't recorded in time, e.g. the instance crashed, the delivery is attempted again.
+	query := `
+		UPDATE webhook_deliveries d SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM movie_changes c, webhooks w
+		WHERE d.id IN (
+			SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+			ORDER BY d.next_attempt_at ASC, d.id ASC
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		) AND c.seq = d.change_seq AND w.id = d.webhook_id
+		RETURNING ` + deliveryColumns + `, ` + webhookColumns
+	rs, err := tx.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	deliveries := []*greenlight.WebhookDelivery{}
+	for rs.Next() {
+		d := greenlight.WebhookDelivery{Webhook: &greenlight.Webhook{}}
+		wdest, done := webhookDest(d.Webhook)
+		if err := rs.Scan(append(deliveryDest(&d), wdest...)...); err != nil {
+			return nil, err
+		}
+		done()
+		deliveries = append(deliveries, &d)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookService) RecordAttempt(ctx context.Context, d *greenlight.WebhookDelivery) (_ int, err error) {
+	defer multierr.Wrap(&err, "postgres.WebhookService.RecordAttempt(%d)", d.ID)
+
+	ctx, cancel := context.WithTimeout(ctx, s.db.opts.queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		UPDATE webhook_deliveries SET (status, attempts, next_attempt_at, status_code, error, last_attempt_at) =
+			($1, $2, $3, NULLIF($4, 0), NULLIF($5, ''), $6)
+		WHERE id = $7`
+	args := []any{d.Status, d.Attempts, d.NextAttemptAt, d.StatusCode, d.Error, nullTime(d.LastAttemptAt), d.ID}
+	rs, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, greenlight.ErrNotFound
+	}
+
+	// Only the completed deliveries are counted, retries of a pending one don't change the failures.
+	query = `
+		UPDATE webhooks SET failures = CASE $2::text WHEN 'succeeded' THEN 0 WHEN 'failed' THEN failures+1 ELSE failures END
+		WHERE id = $1
+		RETURNING failures`
+	var failures int
+	if err := tx.QueryRowContext(ctx, query, d.WebhookID, d.Status).Scan(&failures); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, greenlight.ErrNotFound
+		default:
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
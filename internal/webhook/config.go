@@ -0,0 +1,70 @@
+package webhook
+
+import "time"
+
+// Option represents a configuration option for a [Dispatcher].
+type Option func(o *options)
+
+// options represents all dispatcher options.
+type options struct {
+	pollInterval time.Duration
+	batchSize    int
+	timeout      time.Duration
+	maxAttempts  int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	maxFailures  int
+	// privateTargets reports whether the deliveries to the addresses that aren't publicly routable are allowed.
+	privateTargets bool
+}
+
+// WithPollInterval sets the interval of checking for the pending deliveries.
+func WithPollInterval(t time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = t
+	}
+}
+
+// WithBatchSize sets the maximum number of the deliveries attempted concurrently.
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		o.batchSize = n
+	}
+}
+
+// WithTimeout sets the timeout of a delivery attempt.
+func WithTimeout(t time.Duration) Option {
+	return func(o *options) {
+		o.timeout = t
+	}
+}
+
+// WithMaxAttempts sets the number of the attempts after which a delivery fails.
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry, doubled for each of the following retries up to the maximum.
+func WithBackoff(minDelay, maxDelay time.Duration) Option {
+	return func(o *options) {
+		o.minBackoff = minDelay
+		o.maxBackoff = maxDelay
+	}
+}
+
+// WithMaxFailures sets the number of the consecutive failed deliveries after which a webhook is disabled.
+func WithMaxFailures(n int) Option {
+	return func(o *options) {
+		o.maxFailures = n
+	}
+}
+
+// WithPrivateTargets sets whether the deliveries to the loopback, private and link-local addresses are allowed,
+// e.g. for local development. They're forbidden by default, so that webhooks can't reach the internal services.
+func WithPrivateTargets(allow bool) Option {
+	return func(o *options) {
+		o.privateTargets = allow
+	}
+}
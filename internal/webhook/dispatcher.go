@@ -0,0 +1,262 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+	"github.com/denpeshkov/greenlight/internal/multierr"
+)
+
+// Headers of the delivery requests.
+const (
+	// DeliveryHeader is the ID of the delivery. Retries of the delivery have the same ID.
+	DeliveryHeader = "Greenlight-Delivery"
+	// EventHeader is the operation of the delivered change, see [greenlight.MovieChangeOp].
+	EventHeader = "Greenlight-Event"
+	// TimestampHeader is the Unix time of the attempt. Receivers should reject stale timestamps to prevent replays.
+	TimestampHeader = "Greenlight-Timestamp"
+	// SignatureHeader is the signature of the timestamp and the body, see [Sign].
+	SignatureHeader = "Greenlight-Signature"
+)
+
+// Payload is the body of the delivery requests.
+type Payload struct {
+	Event  greenlight.MovieChangeOp `json:"event"`
+	Change *greenlight.MovieChange  `json:"change"`
+}
+
+// Sign returns the signature of the delivery request sent at the time: "sha256=" followed by the hex-encoded
+// HMAC-SHA256 of the Unix time, a dot and the body, keyed by the secret.
+func Sign(secret string, t time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", t.Unix())
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher delivers the pending webhook deliveries. Any number of dispatchers may run concurrently,
+// each of the deliveries is claimed by a single one of them, see [greenlight.WebhookService.ClaimDeliveries].
+type Dispatcher struct {
+	service greenlight.WebhookService
+	client  *http.Client
+	opts    options
+	logger  *slog.Logger
+}
+
+// NewDispatcher returns a new instance of [Dispatcher].
+func NewDispatcher(service greenlight.WebhookService, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		service: service,
+		opts: options{
+			pollInterval: 5 * time.Second,
+			batchSize:    50,
+			timeout:      10 * time.Second,
+			maxAttempts:  8,
+			minBackoff:   30 * time.Second,
+			maxBackoff:   time.Hour,
+			maxFailures:  5,
+		},
+		logger: newLogger(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !d.opts.privateTargets {
+		dialer.Control = checkTarget
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Proxies aren't used, since the targets are checked when dialing them.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	d.client = &http.Client{
+		Transport: transport,
+		Timeout:   d.opts.timeout,
+		// Redirects aren't followed, the URL of the webhook must be the final one.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return d
+}
+
+// Run delivers the pending deliveries every poll interval until the context is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// Full batches are followed immediately, since more deliveries are likely pending.
+		for {
+			n, err := d.Dispatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				d.logger.Error("dispatching webhook deliveries", "error", err)
+			}
+			if err != nil || n < d.opts.batchSize {
+				break
+			}
+		}
+	}
+}
+
+// Dispatch attempts a batch of the pending deliveries concurrently and returns the number of the attempted ones.
+func (d *Dispatcher) Dispatch(ctx context.Context) (_ int, err error) {
+	defer multierr.Wrap(&err, "webhook.Dispatcher.Dispatch")
+
+	// The lease covers the attempts with a margin for recording them.
+	deliveries, err := d.service.ClaimDeliveries(ctx, d.opts.batchSize, 2*d.opts.timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	errs := make([]error, len(deliveries))
+	var wg sync.WaitGroup
+	for i, dl := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = d.deliver(ctx, dl)
+		}()
+	}
+	wg.Wait()
+
+	if err := multierr.Join(errs...); err != nil {
+		return len(deliveries), err
+	}
+	return len(deliveries), nil
+}
+
+// deliver makes an attempt of the delivery and records its result. If the delivery fails repeatedly,
+// the webhook is disabled.
+func (d *Dispatcher) deliver(ctx context.Context, dl *greenlight.WebhookDelivery) (err error) {
+	defer multierr.Wrap(&err, "webhook.Dispatcher.deliver(%d)", dl.ID)
+
+	now := time.Now()
+	statusCode, err := d.send(ctx, dl, now)
+
+	dl.Attempts++
+	dl.LastAttemptAt = now
+	dl.StatusCode = statusCode
+	dl.Error = ""
+	switch {
+	case err == nil:
+		dl.Status = greenlight.DeliverySucceeded
+	case dl.Attempts >= d.opts.maxAttempts:
+		dl.Status = greenlight.DeliveryFailed
+		dl.Error = err.Error()
+	default:
+		dl.NextAttemptAt = now.Add(d.backoff(dl.Attempts))
+		dl.Error = err.Error()
+	}
+
+	failures, err := d.service.RecordAttempt(ctx, dl)
+	// The webhook is deleted, so the delivery is deleted as well.
+	if errors.Is(err, greenlight.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if failures >= d.opts.maxFailures {
+		d.logger.Info("disabling failing webhook", "webhook_id", dl.WebhookID, "failures", failures)
+		if err := d.service.Disable(ctx, dl.WebhookID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send sends the delivery request at the time and returns the response status code, 0 if there was no response.
+// Responses with a status code other than 2xx are reported as errors.
+func (d *Dispatcher) send(ctx context.Context, dl *greenlight.WebhookDelivery, t time.Time) (int, error) {
+	body, err := json.Marshal(Payload{Event: dl.Change.Op, Change: dl.Change})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Greenlight-Webhook/"+greenlight.AppVersion)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(dl.ID, 10))
+	req.Header.Set(EventHeader, string(dl.Change.Op))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(t.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(dl.Webhook.Secret, t, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain the body, so that the connection is reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// checkTarget rejects the connections to the addresses that aren't publicly routable, e.g. loopback, private and
+// link-local ones. The address is checked when dialing, after it's resolved, so that it can't be bypassed with DNS.
+func checkTarget(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if ip = ip.Unmap(); !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return fmt.Errorf("delivering to non-public address %s is forbidden", ip)
+	}
+	return nil
+}
+
+// backoff returns the delay before the retry following the attempt, starting from 1.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.opts.minBackoff
+	for i := 1; i < attempt && delay < d.opts.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.maxBackoff)
+}
+
+// newLogger returns a dispatcher logger.
+func newLogger() *slog.Logger {
+	opts := slog.HandlerOptions{Level: slog.LevelDebug}
+	handler := slog.NewJSONHandler(os.Stderr, &opts)
+
+	logger := slog.New(handler).With("module", "webhook")
+
+	return logger
+}
@@ -0,0 +1,325 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/denpeshkov/greenlight/internal/greenlight"
+)
+
+const secret = "0123456789abcdef"
+
+// webhookService is an in-memory [greenlight.WebhookService] holding the deliveries to claim.
+type webhookService struct {
+	greenlight.WebhookService
+
+	mu         sync.Mutex
+	pending    []*greenlight.WebhookDelivery
+	attempts   []greenlight.WebhookDelivery
+	failures   map[int64]int
+	disabled   map[int64]bool
+	claimLease time.Duration
+}
+
+func newWebhookService(deliveries ...*greenlight.WebhookDelivery) *webhookService {
+	return &webhookService{
+		pending:  deliveries,
+		failures: make(map[int64]int),
+		disabled: make(map[int64]bool),
+	}
+}
+
+func (s *webhookService) ClaimDeliveries(_ context.Context, limit int, lease time.Duration) ([]*greenlight.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.claimLease = lease
+	n := min(limit, len(s.pending))
+	claimed := s.pending[:n]
+	s.pending = s.pending[n:]
+	return claimed, nil
+}
+
+func (s *webhookService) RecordAttempt(_ context.Context, d *greenlight.WebhookDelivery) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts = append(s.attempts, *d)
+	switch d.Status {
+	case greenlight.DeliverySucceeded:
+		s.failures[d.WebhookID] = 0
+	case greenlight.DeliveryFailed:
+		s.failures[d.WebhookID]++
+	case greenlight.DeliveryPending:
+		s.pending = append(s.pending, d)
+	}
+	return s.failures[d.WebhookID], nil
+}
+
+func (s *webhookService) Disable(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.disabled[id] = true
+	return nil
+}
+
+func newDelivery(id int64, url string) *greenlight.WebhookDelivery {
+	return &greenlight.WebhookDelivery{
+		ID:        id,
+		WebhookID: 1,
+		Status:    greenlight.DeliveryPending,
+		Change: &greenlight.MovieChange{
+			Seq:       id,
+			MovieID:   42,
+			Op:        greenlight.MovieUpdated,
+			Version:   3,
+			ChangedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		},
+		Webhook: &greenlight.Webhook{ID: 1, URL: url, Secret: secret, Active: true},
+	}
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		reqs []*http.Request
+		body []byte
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		reqs = append(reqs, r)
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	s := newWebhookService(newDelivery(7, receiver.URL))
+	d := NewDispatcher(s, WithTimeout(time.Second), WithPrivateTargets(true))
+	before := time.Now().Truncate(time.Second)
+	n, err := d.Dispatch(context.Background())
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("want dispatched: 1, got: %d", n)
+	}
+	if s.claimLease != 2*time.Second {
+		t.Errorf("want lease: %s, got: %s", 2*time.Second, s.claimLease)
+	}
+
+	if len(reqs) != 1 {
+		t.Fatalf("want requests: 1, got: %d", len(reqs))
+	}
+	r := reqs[0]
+	if r.Method != http.MethodPost {
+		t.Errorf("want method: %s, got: %s", http.MethodPost, r.Method)
+	}
+	if got := r.Header.Get(DeliveryHeader); got != "7" {
+		t.Errorf("want delivery header: %q, got: %q", "7", got)
+	}
+	if got := r.Header.Get(EventHeader); got != "updated" {
+		t.Errorf("want event header: %q, got: %q", "updated", got)
+	}
+
+	ts, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("parse timestamp: %v", err)
+	}
+	sent := time.Unix(ts, 0)
+	if sent.Before(before) || sent.After(time.Now()) {
+		t.Errorf("want timestamp about now, got: %s", sent)
+	}
+	if got, want := r.Header.Get(SignatureHeader), Sign(secret, sent, body); got != want {
+		t.Errorf("want signature: %q, got: %q", want, got)
+	}
+
+	var p Payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if p.Event != greenlight.MovieUpdated || p.Change.MovieID != 42 || p.Change.Version != 3 {
+		t.Errorf("unexpected payload: %s", body)
+	}
+
+	if len(s.attempts) != 1 {
+		t.Fatalf("want attempts: 1, got: %d", len(s.attempts))
+	}
+	a := s.attempts[0]
+	if a.Status != greenlight.DeliverySucceeded || a.Attempts != 1 || a.StatusCode != http.StatusNoContent || a.Error != "" {
+		t.Errorf("unexpected attempt: status: %s, attempts: %d, status code: %d, error: %q", a.Status, a.Attempts, a.StatusCode, a.Error)
+	}
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	s := newWebhookService(newDelivery(1, receiver.URL))
+	d := NewDispatcher(s, WithMaxAttempts(3), WithBackoff(time.Minute, 90*time.Second), WithPrivateTargets(true))
+	for range 3 {
+		if _, err := d.Dispatch(context.Background()); err != nil {
+			t.Fatalf("dispatch: %v", err)
+		}
+	}
+
+	if len(s.attempts) != 3 {
+		t.Fatalf("want attempts: 3, got: %d", len(s.attempts))
+	}
+	for i, want := range []time.Duration{time.Minute, 90 * time.Second} {
+		a := s.attempts[i]
+		if a.Status != greenlight.DeliveryPending {
+			t.Errorf("attempt %d: want status: %s, got: %s", i+1, greenlight.DeliveryPending, a.Status)
+		}
+		if got := a.NextAttemptAt.Sub(a.LastAttemptAt); got != want {
+			t.Errorf("attempt %d: want backoff: %s, got: %s", i+1, want, got)
+		}
+	}
+	a := s.attempts[2]
+	if a.Status != greenlight.DeliveryFailed || a.Attempts != 3 || a.StatusCode != http.StatusServiceUnavailable || a.Error == "" {
+		t.Errorf("unexpected attempt: status: %s, attempts: %d, status code: %d, error: %q", a.Status, a.Attempts, a.StatusCode, a.Error)
+	}
+	if len(s.pending) != 0 {
+		t.Errorf("want no pending deliveries, got: %d", len(s.pending))
+	}
+}
+
+func TestDispatcherDisable(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer receiver.Close()
+
+	s := newWebhookService(newDelivery(1, receiver.URL), newDelivery(2, receiver.URL))
+	d := NewDispatcher(s, WithMaxAttempts(1), WithMaxFailures(2), WithPrivateTargets(true))
+
+	if _, err := d.Dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	for _, a := range s.attempts {
+		// Redirects aren't followed.
+		if a.Status != greenlight.DeliveryFailed || a.StatusCode != http.StatusFound {
+			t.Errorf("delivery %d: want failed with status code: %d, got: %s with %d", a.ID, http.StatusFound, a.Status, a.StatusCode)
+		}
+	}
+	if !s.disabled[1] {
+		t.Errorf("want webhook disabled after %d failures", 2)
+	}
+}
+
+func TestDispatcherUnreachable(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := receiver.URL
+	receiver.Close()
+
+	s := newWebhookService(newDelivery(1, url))
+	d := NewDispatcher(s, WithPrivateTargets(true))
+	if _, err := d.Dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	a := s.attempts[0]
+	if a.Status != greenlight.DeliveryPending || a.StatusCode != 0 || a.Error == "" {
+		t.Errorf("unexpected attempt: status: %s, status code: %d, error: %q", a.Status, a.StatusCode, a.Error)
+	}
+	if s.disabled[1] {
+		t.Error("want webhook not disabled while retrying")
+	}
+}
+
+func TestDispatcherPrivateTarget(t *testing.T) {
+	var called bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	s := newWebhookService(newDelivery(1, receiver.URL))
+	d := NewDispatcher(s)
+	if _, err := d.Dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	if called {
+		t.Error("want loopback receiver not called")
+	}
+	a := s.attempts[0]
+	if a.Status != greenlight.DeliveryPending || a.StatusCode != 0 || a.Error == "" {
+		t.Errorf("unexpected attempt: status: %s, status code: %d, error: %q", a.Status, a.StatusCode, a.Error)
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.0.0.1:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"[fe80::1]:80", false},
+		{"[fd00::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"0.0.0.0:80", false},
+	}
+	for _, tt := range tests {
+		if err := checkTarget("tcp", tt.address, nil); (err == nil) != tt.allowed {
+			t.Errorf("address %s: want allowed: %t, got error: %v", tt.address, tt.allowed, err)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil, WithBackoff(30*time.Second, 5*time.Minute))
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempt); got != tt.want {
+			t.Errorf("attempt %d: want: %s, got: %s", tt.attempt, tt.want, got)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	ts := time.Unix(1714557600, 0)
+	body := []byte(`{"event":"created"}`)
+
+	sig := Sign(secret, ts, body)
+	if sig != Sign(secret, ts, body) {
+		t.Error("want deterministic signature")
+	}
+	for name, other := range map[string]string{
+		"secret":    Sign("fedcba9876543210", ts, body),
+		"timestamp": Sign(secret, ts.Add(time.Second), body),
+		"body":      Sign(secret, ts, []byte(`{"event":"deleted"}`)),
+	} {
+		if other == sig {
+			t.Errorf("want signature depending on the %s", name)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package webhook implements delivering the movie changes to webhooks.
+package webhook